@@ -0,0 +1,184 @@
+package venom
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/rockbears/yaml"
+	"github.com/spf13/cast"
+)
+
+// aggregatePercentiles are the percentiles computed on each numeric result of a ranged step
+var aggregatePercentiles = []float64{50, 90, 95, 99}
+
+// Parse the "aggregate_assertions" attribute of an interpolated step. ok is false if the step has none.
+func parseAggregateAssertions(content []byte) (assertions []Assertion, ok bool, err error) {
+	var aggregate struct {
+		AggregateAssertions []Assertion `json:"aggregate_assertions" yaml:"aggregate_assertions"`
+	}
+	if err := yaml.Unmarshal(content, &aggregate); err != nil {
+		return nil, false, fmt.Errorf("unable to parse \"aggregate_assertions\": %v", err)
+	}
+	return aggregate.AggregateAssertions, len(aggregate.AggregateAssertions) > 0, nil
+}
+
+// runAggregateAssertions evaluates the "aggregate_assertions" of a ranged step, once all iterations are done.
+// Failures of the iterations are then reported as warnings: the aggregate assertions decide of the step status.
+func (v *Venom) runAggregateAssertions(ctx context.Context, tc *TestCase, aggregateAssertions []Assertion, stepNumber int, results []TestStepResult) *TestStepResult {
+	tsResult := TestStepResult{
+		Name:         fmt.Sprintf("%s (aggregate)", strings.Split(results[0].Name, " (range=")[0]),
+		Number:       stepNumber,
+		RangedIndex:  len(results),
+		RangedEnable: true,
+		Start:        results[0].Start,
+		End:          time.Now(),
+	}
+
+	tsResult.ComputedVars = computeAggregateVars(results)
+	Debug(ctx, "aggregated results of step #%d: %v", stepNumber, tsResult.ComputedVars)
+
+	step := TestStep{"assertions": aggregateAssertions}
+	assertRes := applyAssertions(ctx, tsResult.ComputedVars, *tc, stepNumber, len(results), step, nil)
+	tsResult.AssertionsApplied = assertRes
+	tsResult.appendFailure(assertRes.errors...)
+	tsResult.Warnings = append(tsResult.Warnings, assertRes.warnings...)
+	tsResult.Duration = tsResult.End.Sub(tsResult.Start).Seconds()
+
+	if len(tsResult.Errors) > 0 || !assertRes.OK {
+		tsResult.Status = StatusFail
+	} else {
+		tsResult.Status = StatusPass
+	}
+	demoteRangedFailures(results)
+	return &tsResult
+}
+
+// pendingStep is an iteration of a ranged step whose result is reported once the aggregate assertions are evaluated
+type pendingStep struct {
+	index int
+	ctx   context.Context
+	span  *span
+}
+
+// reportPendingSteps ends the spans and reports the results of the pending iterations of a ranged step
+func (v *Venom) reportPendingSteps(tc *TestCase, tsIn *TestStepResult, pending []pendingStep) {
+	for _, p := range pending {
+		tsResult := &tc.TestStepResults[p.index]
+		endStepSpan(p.ctx, p.span, tsResult)
+		if tsIn == nil {
+			v.report(func(r Reporter) { r.StepStart(p.ctx, tc, tsResult) })
+		}
+		v.reportTestStepResult(p.ctx, tc, tsResult, tsIn)
+	}
+}
+
+// demoteRangedFailures turns the failures of ranged iterations into warnings
+func demoteRangedFailures(results []TestStepResult) {
+	for i := range results {
+		if results[i].Status != StatusFail {
+			continue
+		}
+		results[i].Warnings = append(results[i].Warnings, results[i].Errors...)
+		results[i].Errors = nil
+		results[i].Status = StatusPass
+	}
+}
+
+// computeAggregateVars computes the variables available in "aggregate_assertions":
+//   - results.count, results.passed, results.failed, results.skipped and results.errorrate
+//   - results.<index>.status and results.<index>.<key> for each numeric result of each iteration
+//   - results.<key>.min, .max, .avg, .sum, .p50, .p90, .p95 and .p99 for each numeric result
+func computeAggregateVars(results []TestStepResult) H {
+	vars := H{}
+	values := map[string][]float64{}
+
+	var passed, failed, skipped int
+	for i, r := range results {
+		switch r.Status {
+		case StatusPass:
+			passed++
+		case StatusFail:
+			failed++
+		case StatusSkip:
+			skipped++
+		}
+		vars.Add(fmt.Sprintf("results.%d.status", i), string(r.Status))
+		vars.Add(fmt.Sprintf("results.%d.duration", i), r.Duration)
+		if r.Status != StatusSkip {
+			values["duration"] = append(values["duration"], r.Duration)
+		}
+
+		for k, val := range r.ComputedVars {
+			lk := strings.ToLower(k)
+			if !strings.HasPrefix(lk, "result.") || strings.HasSuffix(lk, "__type__") || strings.HasSuffix(lk, "__len__") {
+				continue
+			}
+			f, ok := toAggregateNumber(val)
+			if !ok {
+				continue
+			}
+			key := strings.TrimPrefix(k, "result.")
+			vars.Add(fmt.Sprintf("results.%d.%s", i, key), f)
+			values[key] = append(values[key], f)
+		}
+	}
+
+	vars.Add("results.count", len(results))
+	vars.Add("results.passed", passed)
+	vars.Add("results.failed", failed)
+	vars.Add("results.skipped", skipped)
+	if len(results) > 0 {
+		vars.Add("results.errorrate", float64(failed)/float64(len(results)))
+	}
+
+	for key, vals := range values {
+		if len(vals) == 0 {
+			continue
+		}
+		sort.Float64s(vals)
+		var sum float64
+		for _, f := range vals {
+			sum += f
+		}
+		vars.Add("results."+key+".count", len(vals))
+		vars.Add("results."+key+".sum", sum)
+		vars.Add("results."+key+".avg", sum/float64(len(vals)))
+		vars.Add("results."+key+".min", vals[0])
+		vars.Add("results."+key+".max", vals[len(vals)-1])
+		for _, p := range aggregatePercentiles {
+			vars.Add(fmt.Sprintf("results.%s.p%d", key, int(p)), percentile(vals, p))
+		}
+	}
+	return vars
+}
+
+func toAggregateNumber(val interface{}) (float64, bool) {
+	switch val.(type) {
+	case bool, nil:
+		return 0, false
+	}
+	f, err := cast.ToFloat64E(val)
+	if err != nil || math.IsNaN(f) {
+		return 0, false
+	}
+	return f, true
+}
+
+// percentile returns the p-th percentile of sorted values, using the nearest-rank method
+func percentile(sorted []float64, p float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	if rank > len(sorted) {
+		rank = len(sorted)
+	}
+	return sorted[rank-1]
+}
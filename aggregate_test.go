@@ -0,0 +1,91 @@
+package venom
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_computeAggregateVars(t *testing.T) {
+	results := []TestStepResult{
+		{Status: StatusPass, ComputedVars: H{"result.timeseconds": 0.1, "result.statuscode": 200, "result.body": "foo", "result.__Len__": 3}},
+		{Status: StatusPass, ComputedVars: H{"result.timeseconds": 0.3, "result.statuscode": 200, "result.body": "bar"}},
+		{Status: StatusFail, ComputedVars: H{"result.timeseconds": 0.2, "result.statuscode": 500, "result.body": "baz"}},
+		{Status: StatusPass, ComputedVars: H{"result.timeseconds": 0.4, "result.statuscode": 200, "result.body": "qux"}},
+	}
+
+	vars := computeAggregateVars(results)
+	assert.Equal(t, 4, vars["results.count"])
+	assert.Equal(t, 3, vars["results.passed"])
+	assert.Equal(t, 1, vars["results.failed"])
+	assert.Equal(t, 0.25, vars["results.errorrate"])
+	assert.Equal(t, 0.1, vars["results.timeseconds.min"])
+	assert.Equal(t, 0.4, vars["results.timeseconds.max"])
+	assert.Equal(t, 0.2, vars["results.timeseconds.p50"])
+	assert.Equal(t, 0.4, vars["results.timeseconds.p95"])
+	assert.Equal(t, 500.0, vars["results.statuscode.max"])
+	assert.Equal(t, 500.0, vars["results.2.statuscode"])
+	assert.Equal(t, "FAIL", vars["results.2.status"])
+	assert.NotContains(t, vars, "results.body.max")
+	assert.NotContains(t, vars, "results.__Len__.max")
+}
+
+func Test_percentile(t *testing.T) {
+	values := []float64{1, 2, 3, 4, 5, 6, 7, 8, 9, 10}
+	assert.Equal(t, 5.0, percentile(values, 50))
+	assert.Equal(t, 10.0, percentile(values, 95))
+	assert.Equal(t, 10.0, percentile(values, 99))
+	assert.Equal(t, 0.0, percentile(nil, 50))
+}
+
+const aggregateTestSuite = `name: aggregate
+testcases:
+- name: range
+  steps:
+  - type: echo
+    range: [hello, goodbye, hello]
+    value: '{{.value}}'
+    assertions:
+    - out ShouldEqual hello
+    aggregate_assertions:
+    - results.count ShouldEqual 3
+    - results.failed ShouldBeLessThanOrEqualTo {{.threshold}}
+`
+
+func TestAggregateAssertions(t *testing.T) {
+	for _, tt := range []struct {
+		threshold int
+		events    []string
+	}{
+		{threshold: 1, events: []string{"step_end PASS", "step_end PASS", "step_end PASS", "step_end PASS", "testcase_end range PASS"}},
+		{threshold: 0, events: []string{"step_end PASS", "step_end PASS", "step_end PASS", "step_end FAIL", "testcase_end range FAIL"}},
+	} {
+		t.Run(fmt.Sprintf("threshold %d", tt.threshold), func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "aggregate.yml"), []byte(aggregateTestSuite), 0644))
+			r := &recordTestReporter{}
+			tests, err := Run(context.Background(), Options{
+				Paths:     []string{filepath.Join(dir, "aggregate.yml")},
+				Variables: map[string]interface{}{"threshold": tt.threshold},
+				Executors: map[string]Executor{"echo": &debugTestExecutor{}},
+				Reporters: []Reporter{r},
+				LogOutput: io.Discard,
+			})
+			require.NoError(t, err)
+			// the reporters only see the status of the iterations once the aggregate assertions are evaluated
+			assert.Equal(t, tt.events, r.events[3:len(r.events)-1])
+
+			results := tests.TestSuites[0].TestCases[0].TestStepResults
+			require.Len(t, results, 4)
+			assert.Equal(t, "echo (aggregate)", results[3].Name)
+			assert.Len(t, results[1].Warnings, 1)
+			assert.Empty(t, results[1].Errors)
+		})
+	}
+}
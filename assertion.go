@@ -16,9 +16,17 @@ import (
 	"github.com/ovh/venom/assertions"
 )
 
+const (
+	// SeverityError makes the step fail when the assertion fails
+	SeverityError = "error"
+	// SeverityWarning only reports the assertion failure, the step keeps its status
+	SeverityWarning = "warning"
+)
+
 type AssertionsApplied struct {
 	OK         bool `json:"ok" yml:"-"`
 	errors     []Failure
+	warnings   []Failure
 	systemout  string
 	systemerr  string
 	Assertions []AssertionApplied `json:"assertions" yml:"-"`
@@ -26,17 +34,18 @@ type AssertionsApplied struct {
 type AssertionApplied struct {
 	Assertion Assertion `json:"assertion" yml:"-"`
 	IsOK      bool      `json:"isOK" yml:"-"`
+	Severity  string    `json:"severity,omitempty" yml:"-"`
 }
 
 func applyAssertions(ctx context.Context, r interface{}, tc TestCase, stepNumber int, rangedIndex int, step TestStep, defaultAssertions *StepAssertions) AssertionsApplied {
 	var sa StepAssertions
-	var errors []Failure
+	var errors, warnings []Failure
 	var systemerr, systemout string
 
 	if err := mapstructure.Decode(step, &sa); err != nil {
 		return AssertionsApplied{
 			OK:        false,
-			errors:    []Failure{{Value: RemoveNotPrintableChar(fmt.Sprintf("error decoding assertions: %s", err))}},
+			errors:    []Failure{{Type: FailureTypeError, Value: RemoveNotPrintableChar(fmt.Sprintf("error decoding assertions: %s", err))}},
 			systemout: systemout,
 			systemerr: systemerr,
 		}
@@ -53,14 +62,21 @@ func applyAssertions(ctx context.Context, r interface{}, tc TestCase, stepNumber
 	for _, assertion := range sa.Assertions {
 		errs := check(ctx, tc, stepNumber, rangedIndex, assertion, executorResult)
 		isAssertionOK := true
+		var severity string
 		if errs != nil {
-			errors = append(errors, *errs)
-			isOK = false
 			isAssertionOK = false
+			if errs.Severity == SeverityWarning {
+				severity = SeverityWarning
+				warnings = append(warnings, *errs)
+			} else {
+				errors = append(errors, *errs)
+				isOK = false
+			}
 		}
 		assertions = append(assertions, AssertionApplied{
 			Assertion: assertion,
 			IsOK:      isAssertionOK,
+			Severity:  severity,
 		})
 	}
 
@@ -75,6 +91,7 @@ func applyAssertions(ctx context.Context, r interface{}, tc TestCase, stepNumber
 	return AssertionsApplied{
 		OK:         isOK,
 		errors:     errors,
+		warnings:   warnings,
 		systemerr:  systemerr,
 		systemout:  systemout,
 		Assertions: assertions,
@@ -86,6 +103,7 @@ type assertion struct {
 	Func     assertions.AssertFunc
 	Args     []interface{}
 	Required bool
+	Warning  bool
 }
 
 func parseAssertions(ctx context.Context, s string, input interface{}) (*assertion, error) {
@@ -106,6 +124,13 @@ func parseAssertions(ctx context.Context, s string, input interface{}) (*asserti
 		assert[1] = strings.Replace(assert[1], "Must", "Should", 1)
 	}
 
+	// "Warn" assertions use same tests as "Should" ones, but failures are only reported
+	warning := false
+	if strings.HasPrefix(assert[1], "Warn") {
+		warning = true
+		assert[1] = strings.Replace(assert[1], "Warn", "Should", 1)
+	}
+
 	f, ok := assertions.Get(assert[1])
 	if !ok {
 		return nil, errors.New("assertion not supported")
@@ -123,6 +148,7 @@ func parseAssertions(ctx context.Context, s string, input interface{}) (*asserti
 		Func:     f,
 		Args:     args,
 		Required: required,
+		Warning:  warning,
 	}, nil
 }
 
@@ -143,6 +169,16 @@ func check(ctx context.Context, tc TestCase, stepNumber int, rangedIndex int, as
 // checkString evaluate a complex assertion containing logical operators
 // it recursively calls checkAssertion for each operand
 func checkBranch(ctx context.Context, tc TestCase, stepNumber int, rangedIndex int, branch map[string]interface{}, r interface{}) *Failure {
+	// Extract severity, if any
+	severity := SeverityError
+	if s, ok := branch["severity"]; ok {
+		severity = fmt.Sprint(s)
+		if severity != SeverityError && severity != SeverityWarning {
+			return newFailure(ctx, tc, stepNumber, rangedIndex, "", fmt.Errorf("unsupported assertion severity %q", severity))
+		}
+		branch = copyWithout(branch, "severity")
+	}
+
 	// Extract logical operator
 	if len(branch) != 1 {
 		return newFailure(ctx, tc, stepNumber, rangedIndex, "", fmt.Errorf("expected exactly 1 logical operator but %d were provided", len(branch)))
@@ -205,21 +241,41 @@ func checkBranch(ctx context.Context, tc TestCase, stepNumber int, rangedIndex i
 		return newFailure(ctx, tc, stepNumber, rangedIndex, "", fmt.Errorf("unsupported assertion operator %s", operator))
 	}
 	if err != nil {
-		return newFailure(ctx, tc, stepNumber, rangedIndex, "", err)
+		failure := newFailure(ctx, tc, stepNumber, rangedIndex, "", err)
+		failure.Type = FailureTypeAssertion
+		if severity == SeverityWarning {
+			failure.Severity = SeverityWarning
+		}
+		return failure
 	}
 	return nil
 }
 
+func copyWithout(m map[string]interface{}, key string) map[string]interface{} {
+	res := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		if k != key {
+			res[k] = v
+		}
+	}
+	return res
+}
+
 // checkString evaluate a single string assertion
 func checkString(ctx context.Context, tc TestCase, stepNumber int, rangedIndex int, assertion string, r interface{}) *Failure {
 	assert, err := parseAssertions(context.Background(), assertion, r)
 	if err != nil {
-		return newFailure(ctx, tc, stepNumber, rangedIndex, assertion, err)
+		failure := newFailure(ctx, tc, stepNumber, rangedIndex, assertion, err)
+		failure.Type = FailureTypeError
+		return failure
 	}
 
 	if err := assert.Func(assert.Actual, assert.Args...); err != nil {
 		failure := newFailure(ctx, tc, stepNumber, rangedIndex, assertion, err)
 		failure.AssertionRequired = assert.Required
+		if assert.Warning {
+			failure.Severity = SeverityWarning
+		}
 		return failure
 	}
 	return nil
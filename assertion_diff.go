@@ -0,0 +1,81 @@
+package venom
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/ovh/venom/assertions"
+)
+
+// maxDiffLines limits the number of diff lines printed on the console
+const maxDiffLines = 50
+
+// FormatDiff renders a diff as text, each line prefixed by indent.
+// Structural changes are preferred over the unified text diff when available.
+// When colored is true, removed lines are printed in red and added lines in green.
+func FormatDiff(d *assertions.Diff, indent string, colored bool) string {
+	if d == nil {
+		return ""
+	}
+	red, green, cyan := fmt.Sprint, fmt.Sprint, fmt.Sprint
+	if colored {
+		red, green, cyan = Red, Green, Cyan
+	}
+
+	var lines []string
+	if len(d.Changes) > 0 {
+		for _, c := range d.Changes {
+			switch c.Kind {
+			case assertions.DiffRemoved:
+				lines = append(lines, red(fmt.Sprintf("- %s: %s", c.Path, diffValue(c.Expected))))
+			case assertions.DiffAdded:
+				lines = append(lines, green(fmt.Sprintf("+ %s: %s", c.Path, diffValue(c.Actual))))
+			default:
+				lines = append(lines, red(fmt.Sprintf("- %s: %s", c.Path, diffValue(c.Expected))))
+				lines = append(lines, green(fmt.Sprintf("+ %s: %s", c.Path, diffValue(c.Actual))))
+			}
+		}
+	} else {
+		for _, l := range strings.Split(strings.TrimRight(d.Unified, "\n"), "\n") {
+			switch {
+			case strings.HasPrefix(l, "---"), strings.HasPrefix(l, "+++"):
+				lines = append(lines, l)
+			case strings.HasPrefix(l, "-"):
+				lines = append(lines, red(l))
+			case strings.HasPrefix(l, "+"):
+				lines = append(lines, green(l))
+			case strings.HasPrefix(l, "@@"):
+				lines = append(lines, cyan(l))
+			default:
+				lines = append(lines, l)
+			}
+		}
+	}
+
+	if colored && len(lines) > maxDiffLines {
+		skipped := len(lines) - maxDiffLines
+		lines = append(lines[:maxDiffLines], Gray(fmt.Sprintf("... %d more line(s), see the test report", skipped)))
+	}
+
+	for i := range lines {
+		lines[i] = indent + lines[i]
+	}
+	return strings.Join(lines, "\n")
+}
+
+func diffValue(v interface{}) string {
+	btes, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(btes)
+}
+
+// failureWithDiff returns the failure value followed by its uncolored diff, if any
+func failureWithDiff(f Failure) string {
+	if f.Diff == nil {
+		return f.Value
+	}
+	return f.Value + "\n" + FormatDiff(f.Diff, "", false)
+}
@@ -1,8 +1,14 @@
 package venom
 
 import (
+	"context"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_splitAssertion(t *testing.T) {
@@ -22,3 +28,78 @@ func Test_splitAssertion(t *testing.T) {
 		}
 	}
 }
+
+func Test_applyAssertionsSeverity(t *testing.T) {
+	result := map[string]interface{}{"out": "hello", "code": 0}
+	for _, tt := range []struct {
+		name      string
+		assertion Assertion
+		ok        bool
+		warnings  int
+		errors    int
+		severity  string
+	}{
+		{name: "passing warn prefix", assertion: "out WarnEqual hello", ok: true},
+		{name: "failing warn prefix", assertion: "out WarnEqual bye", ok: true, warnings: 1, severity: SeverityWarning},
+		{name: "failing should", assertion: "out ShouldEqual bye", ok: false, errors: 1},
+		{name: "failing warning map", assertion: map[string]interface{}{"severity": "warning", "and": []interface{}{"out ShouldEqual bye", "code ShouldEqual 0"}}, ok: true, warnings: 1, severity: SeverityWarning},
+		{name: "failing error map", assertion: map[string]interface{}{"severity": "error", "or": []interface{}{"out ShouldEqual bye"}}, ok: false, errors: 1},
+		{name: "invalid severity", assertion: map[string]interface{}{"severity": "info", "and": []interface{}{"out ShouldEqual hello"}}, ok: false, errors: 1},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			res := applyAssertions(context.Background(), result, TestCase{}, 0, 0, TestStep{"assertions": []interface{}{tt.assertion}}, nil)
+			assert.Equal(t, tt.ok, res.OK)
+			assert.Len(t, res.warnings, tt.warnings)
+			assert.Len(t, res.errors, tt.errors)
+			require.Len(t, res.Assertions, 1)
+			assert.Equal(t, tt.severity, res.Assertions[0].Severity)
+			if tt.name == "invalid severity" {
+				assert.Contains(t, res.errors[0].Value, `unsupported assertion severity "info"`)
+			}
+		})
+	}
+}
+
+// assertionTestExecutor returns the value of its step as out
+type assertionTestExecutor struct{}
+
+func (assertionTestExecutor) Run(ctx context.Context, step TestStep) (interface{}, error) {
+	out, _ := step.StringValue("value")
+	return map[string]interface{}{"out": out}, nil
+}
+
+func TestWarningAssertions(t *testing.T) {
+	InitTestLogger(t)
+	dir := t.TempDir()
+	testsuite := `name: warnings
+testcases:
+- name: warn
+  steps:
+  - type: echo
+    value: hello
+    assertions:
+    - out ShouldEqual hello
+    - out WarnEqual bye
+    - severity: warning
+      or:
+      - out ShouldEqual bye
+`
+	filename := filepath.Join(dir, "warnings.yml")
+	require.NoError(t, os.WriteFile(filename, []byte(testsuite), 0644))
+
+	v := New()
+	v.PrintFunc = func(format string, a ...interface{}) (int, error) { return 0, nil }
+	v.RegisterExecutorBuiltin("echo", assertionTestExecutor{})
+	ctx := context.Background()
+	require.NoError(t, v.Parse(ctx, []string{filename}))
+	require.NoError(t, v.Process(ctx, []string{filename}))
+	assert.Equal(t, StatusPass, v.Tests.Status)
+
+	tc := v.Tests.TestSuites[0].TestCases[0]
+	assert.Equal(t, StatusPass, tc.Status)
+	require.Len(t, tc.TestStepResults, 1)
+	step := tc.TestStepResults[0]
+	assert.Equal(t, StatusPass, step.Status)
+	assert.Empty(t, step.Errors)
+	assert.Len(t, step.Warnings, 2)
+}
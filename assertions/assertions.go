@@ -126,6 +126,9 @@ func ShouldEqual(actual interface{}, expected ...interface{}) error {
 		if deepEqual(actual, strings.TrimRight(args, " ")) {
 			return nil
 		}
+		if actualS := fmt.Sprintf("%v", actual); needDiff(args, actualS) {
+			return newTextDiffError(strings.TrimRight(args, " "), actualS)
+		}
 		return fmt.Errorf("expected: %v got: %v", args, actual)
 	}
 
@@ -1252,7 +1255,7 @@ func ShouldJSONEqual(actual interface{}, expected ...interface{}) error {
 		if reflect.DeepEqual(actualMap, expectedMap) {
 			return nil
 		}
-		return fmt.Errorf("expected '%v' to be JSON equals to '%v' ", actualMap, expectedMap)
+		return newJSONDiffError(expectedMap, actualMap)
 	case []interface{}:
 		actualSlice, err := cast.ToSliceE(actual)
 		if err != nil {
@@ -1281,7 +1284,7 @@ func ShouldJSONEqual(actual interface{}, expected ...interface{}) error {
 		if reflect.DeepEqual(actualSlice, expectedSlice) {
 			return nil
 		}
-		return fmt.Errorf("expected '%v' to be JSON equals to '%v' ", actualSlice, expectedSlice)
+		return newJSONDiffError(expectedSlice, actualSlice)
 	case string:
 		actualString, err := cast.ToStringE(actual)
 		if err != nil {
@@ -1302,6 +1305,9 @@ func ShouldJSONEqual(actual interface{}, expected ...interface{}) error {
 		if actualString == "" && expectedString == "null" {
 			return nil
 		}
+		if needDiff(expectedString, actualString) {
+			return newTextDiffError(expectedString, actualString)
+		}
 		return fmt.Errorf("expected '%v' to be JSON equals to '%v' ", actualString, expectedString)
 	case json.Number:
 		actualFloat, err := cast.ToFloat64E(actual)
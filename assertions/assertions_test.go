@@ -1657,3 +1657,35 @@ func TestShouldJSONEqual(t *testing.T) {
 		})
 	}
 }
+
+func TestShouldJSONEqualDiff(t *testing.T) {
+	actual := map[string]interface{}{"a": "foo", "b": []interface{}{1.0, 2.0}, "c": true}
+	err := ShouldJSONEqual(actual, `{"a": "bar", "b": [1, 2, 3], "d": false}`)
+	assert.Error(t, err)
+
+	d := GetDiff(err)
+	if assert.NotNil(t, d) {
+		assert.Equal(t, []DiffChange{
+			{Path: "$.a", Kind: DiffChanged, Expected: "bar", Actual: "foo"},
+			{Path: "$.b[2]", Kind: DiffRemoved, Expected: 3.0},
+			{Path: "$.c", Kind: DiffAdded, Actual: true},
+			{Path: "$.d", Kind: DiffRemoved, Expected: false},
+		}, d.Changes)
+		assert.Contains(t, d.Unified, `-  "a": "bar",`)
+		assert.Contains(t, d.Unified, `+  "a": "foo",`)
+	}
+}
+
+func TestShouldEqualDiff(t *testing.T) {
+	err := ShouldEqual("line 1\nline 2\nline 3", "line 1\nline two\nline 3")
+	assert.Error(t, err)
+	d := GetDiff(err)
+	if assert.NotNil(t, d) {
+		assert.Contains(t, d.Unified, "-line two")
+		assert.Contains(t, d.Unified, "+line 2")
+	}
+
+	err = ShouldEqual("a", "b")
+	assert.Error(t, err)
+	assert.Nil(t, GetDiff(err))
+}
@@ -0,0 +1,181 @@
+package assertions
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diffMinLength is the length from which a failed comparison produces a diff
+// instead of printing both values inline.
+const diffMinLength = 80
+
+const (
+	DiffAdded   = "added"
+	DiffRemoved = "removed"
+	DiffChanged = "changed"
+)
+
+// Diff describes the differences between an expected and an actual value.
+type Diff struct {
+	Expected string       `json:"expected" yaml:"expected"`
+	Actual   string       `json:"actual" yaml:"actual"`
+	Unified  string       `json:"unified,omitempty" yaml:"unified,omitempty"`
+	Changes  []DiffChange `json:"changes,omitempty" yaml:"changes,omitempty"`
+}
+
+// DiffChange is a single structural difference between two JSON documents.
+type DiffChange struct {
+	Path     string      `json:"path" yaml:"path"`
+	Kind     string      `json:"kind" yaml:"kind"`
+	Expected interface{} `json:"expected,omitempty" yaml:"expected,omitempty"`
+	Actual   interface{} `json:"actual,omitempty" yaml:"actual,omitempty"`
+}
+
+// DiffError is returned by assertions able to explain a mismatch with a diff.
+type DiffError struct {
+	cause error
+	Diff  *Diff
+}
+
+func (e *DiffError) Error() string {
+	return e.cause.Error()
+}
+
+func (e *DiffError) Unwrap() error {
+	return e.cause
+}
+
+// GetDiff returns the diff carried by err, if any.
+func GetDiff(err error) *Diff {
+	var e *DiffError
+	if errors.As(err, &e) {
+		return e.Diff
+	}
+	return nil
+}
+
+// needDiff returns true if values are too long or too complex to be read inline.
+func needDiff(expected, actual string) bool {
+	return len(expected) >= diffMinLength || len(actual) >= diffMinLength ||
+		strings.Contains(expected, "\n") || strings.Contains(actual, "\n")
+}
+
+// newTextDiffError builds an error containing a unified diff between two strings.
+func newTextDiffError(expected, actual string) error {
+	d := &Diff{
+		Expected: expected,
+		Actual:   actual,
+		Unified:  unifiedDiff(expected, actual),
+	}
+	return &DiffError{
+		cause: fmt.Errorf("expected: %s got: %s", truncate(expected), truncate(actual)),
+		Diff:  d,
+	}
+}
+
+// newJSONDiffError builds an error containing a structural diff between two JSON values.
+func newJSONDiffError(expected, actual interface{}) error {
+	expectedBtes, _ := json.MarshalIndent(expected, "", "  ")
+	actualBtes, _ := json.MarshalIndent(actual, "", "  ")
+	d := &Diff{
+		Expected: string(expectedBtes),
+		Actual:   string(actualBtes),
+		Unified:  unifiedDiff(string(expectedBtes), string(actualBtes)),
+		Changes:  diffJSON("$", expected, actual),
+	}
+	return &DiffError{
+		cause: fmt.Errorf("expected value to be JSON equals: %d difference(s) found", len(d.Changes)),
+		Diff:  d,
+	}
+}
+
+func unifiedDiff(expected, actual string) string {
+	diff := difflib.UnifiedDiff{
+		A:        difflib.SplitLines(expected),
+		B:        difflib.SplitLines(actual),
+		FromFile: "expected",
+		ToFile:   "actual",
+		Context:  3,
+	}
+	s, err := difflib.GetUnifiedDiffString(diff)
+	if err != nil {
+		return ""
+	}
+	return s
+}
+
+// diffJSON walks both values and returns every difference, with a JSONPath-like path.
+func diffJSON(path string, expected, actual interface{}) []DiffChange {
+	switch e := expected.(type) {
+	case map[string]interface{}:
+		a, ok := actual.(map[string]interface{})
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(e)+len(a))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		for k := range a {
+			if _, ok := e[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		var changes []DiffChange
+		for _, k := range keys {
+			ev, inExpected := e[k]
+			av, inActual := a[k]
+			p := path + "." + k
+			switch {
+			case !inActual:
+				changes = append(changes, DiffChange{Path: p, Kind: DiffRemoved, Expected: ev})
+			case !inExpected:
+				changes = append(changes, DiffChange{Path: p, Kind: DiffAdded, Actual: av})
+			default:
+				changes = append(changes, diffJSON(p, ev, av)...)
+			}
+		}
+		return changes
+	case []interface{}:
+		a, ok := actual.([]interface{})
+		if !ok {
+			break
+		}
+		var changes []DiffChange
+		for i := 0; i < len(e) || i < len(a); i++ {
+			p := fmt.Sprintf("%s[%d]", path, i)
+			switch {
+			case i >= len(a):
+				changes = append(changes, DiffChange{Path: p, Kind: DiffRemoved, Expected: e[i]})
+			case i >= len(e):
+				changes = append(changes, DiffChange{Path: p, Kind: DiffAdded, Actual: a[i]})
+			default:
+				changes = append(changes, diffJSON(p, e[i], a[i])...)
+			}
+		}
+		return changes
+	}
+
+	if reflect.DeepEqual(expected, actual) {
+		return nil
+	}
+	return []DiffChange{{Path: path, Kind: DiffChanged, Expected: expected, Actual: actual}}
+}
+
+// truncate shortens a value printed inline in an error message
+func truncate(s string) string {
+	s = strings.ReplaceAll(s, "\n", `\n`)
+	r := []rune(s)
+	if len(r) <= diffMinLength/2 {
+		return s
+	}
+	return string(r[:diffMinLength/2]) + "..."
+}
@@ -0,0 +1,100 @@
+package debug
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/venom"
+	"github.com/ovh/venom/cmd/venom/run"
+	"github.com/ovh/venom/executors"
+)
+
+var (
+	breakpoints []string
+	variables   []string
+	varFiles    []string
+	libDir      string
+	verbose     int
+)
+
+func init() {
+	Cmd.Flags().StringArrayVar(&breakpoints, "break", nil, "--break testcase[:step]: pause before a step, given by its number or its name. Without breakpoint, the run is paused before the first step")
+	Cmd.Flags().StringArrayVar(&variables, "var", nil, "--var cds='cds -f config.json' --var cds2='cds -f config.json'")
+	Cmd.Flags().StringSliceVar(&varFiles, "var-from-file", nil, "--var-from-file filename.yaml --var-from-file filename2.yaml: yaml, must contains a dictionary")
+	Cmd.Flags().StringVar(&libDir, "lib-dir", "", "Lib Directory: can contain user executors. example:/etc/venom/lib:$HOME/venom.d/lib")
+	Cmd.Flags().CountVarP(&verbose, "verbose", "v", "verbose. -v (INFO level in venom.log file), -vv to very verbose (DEBUG level)")
+}
+
+// Cmd debug
+var Cmd = &cobra.Command{
+	Use:   "debug",
+	Short: "Run testsuites step by step",
+	Example: `  Pause before the first step of each testcase: venom debug mytestfile.yml
+  Pause before the third step of a testcase: venom debug mytestfile.yml --break "my testcase:2"
+  Pause before a step given by its name: venom debug mytestfile.yml --break "my testcase:create user"`,
+	Long: `debug runs testsuites and pauses before the steps given by --break.
+At a pause, variables and the interpolated step can be inspected and changed, then the step can be run again with its assertions.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d := newDebugger(bufio.NewScanner(os.Stdin), os.Stdout)
+		for _, b := range breakpoints {
+			bp, err := venom.ParseBreakpoint(b)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				venom.OSExit(2)
+			}
+			d.breakpoints = append(d.breakpoints, bp)
+		}
+		d.stepping = len(d.breakpoints) == 0
+
+		opts := venom.Options{
+			Executors: map[string]venom.Executor{},
+			LibDir:    libDir,
+			Verbose:   verbose,
+			Debugger:  d,
+			Output:    os.Stdout,
+		}
+		for name, executorFunc := range executors.Registry {
+			opts.Executors[name] = executorFunc()
+		}
+		if libDir == "" {
+			opts.LibDir = os.Getenv("VENOM_LIB_DIR")
+		}
+		if opts.Verbose == 0 {
+			opts.Verbose = 1
+		}
+
+		ctx := context.Background()
+		mapvars, err := run.LoadVariables(ctx, variables, varFiles)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			venom.OSExit(2)
+		}
+		opts.Variables = mapvars
+
+		v, err := venom.NewWithOptions(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			venom.OSExit(2)
+		}
+		defer v.Close() //nolint
+
+		tests, err := v.Run(v.WithLogger(ctx), args)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			venom.OSExit(2)
+		}
+
+		if tests.Status == venom.StatusPass {
+			fmt.Fprintf(os.Stdout, "final status: %v\n", venom.Green(tests.Status))
+			venom.OSExit(0)
+		}
+		fmt.Fprintf(os.Stdout, "final status: %v\n", venom.Red(tests.Status))
+		venom.OSExit(2)
+		return nil
+	},
+}
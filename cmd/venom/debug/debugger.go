@@ -0,0 +1,199 @@
+package debug
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/rockbears/yaml"
+
+	"github.com/ovh/venom"
+)
+
+const help = `  vars [prefix]    print the variables available in the step
+  step             print the interpolated step
+  results          print the results of the previous steps
+  result           print the result of the step, once it is run
+  set name=value   change a variable, the step is interpolated again
+  next, n          run the step and pause before the next one
+  continue, c      run the steps until the next breakpoint
+  rerun, r         run the step again with its assertions, once it is run
+  quit, q          stop the run`
+
+// debugger is an interactive venom.Debugger reading commands from a terminal
+type debugger struct {
+	breakpoints []venom.Breakpoint
+	in          *bufio.Scanner
+	out         io.Writer
+	// stepping pauses before the next step, whatever the breakpoints
+	stepping bool
+	// current is the step paused
+	current *venom.DebugStep
+	quit    func()
+}
+
+func newDebugger(in *bufio.Scanner, out io.Writer) *debugger {
+	return &debugger{in: in, out: out, quit: func() { venom.OSExit(2) }}
+}
+
+func (d *debugger) BeforeStep(ctx context.Context, s *venom.DebugStep) venom.H {
+	if s != d.current {
+		if !d.stepping && !d.matchBreakpoint(s) {
+			return nil
+		}
+		d.current = s
+		fmt.Fprintf(d.out, "\n%s before step #%d %q of testcase %q\n", venom.Cyan("paused"), s.StepNumber, s.Name, s.TestCaseName)
+		d.printStep(s)
+	} else if s.Err != nil {
+		fmt.Fprintf(d.out, "%s\n", venom.Red(s.Err))
+	} else {
+		d.printStep(s)
+	}
+
+	for {
+		cmd, arg, ok := d.prompt()
+		if !ok {
+			d.stepping = false
+			return nil
+		}
+		switch cmd {
+		case "set":
+			name, value, found := strings.Cut(arg, "=")
+			if !found || name == "" {
+				fmt.Fprintln(d.out, "usage: set name=value")
+				continue
+			}
+			var v interface{}
+			if err := yaml.Unmarshal([]byte(value), &v); err != nil {
+				v = value
+			}
+			return venom.H{name: v}
+		case "next", "n":
+			d.stepping = true
+			return nil
+		case "continue", "c":
+			d.stepping = false
+			return nil
+		case "rerun", "r", "result":
+			fmt.Fprintln(d.out, "the step is not run yet")
+		default:
+			d.inspect(s, cmd, arg)
+		}
+	}
+}
+
+func (d *debugger) AfterStep(ctx context.Context, s *venom.DebugStep) bool {
+	if s != d.current {
+		return false
+	}
+	d.printResult(s.Result)
+
+	for {
+		cmd, arg, ok := d.prompt()
+		if !ok {
+			d.stepping = false
+			return false
+		}
+		switch cmd {
+		case "rerun", "r":
+			return true
+		case "next", "n":
+			d.stepping = true
+			return false
+		case "continue", "c":
+			d.stepping = false
+			return false
+		case "set":
+			fmt.Fprintln(d.out, "variables can only be changed before the step is run, use rerun to run it again")
+		default:
+			d.inspect(s, cmd, arg)
+		}
+	}
+}
+
+func (d *debugger) matchBreakpoint(s *venom.DebugStep) bool {
+	for _, b := range d.breakpoints {
+		if b.Match(s) {
+			return true
+		}
+	}
+	return false
+}
+
+// prompt reads a command, it returns false at the end of the input
+func (d *debugger) prompt() (string, string, bool) {
+	fmt.Fprint(d.out, "(venom) ")
+	if !d.in.Scan() {
+		fmt.Fprintln(d.out)
+		return "", "", false
+	}
+	line := strings.TrimSpace(d.in.Text())
+	cmd, arg, _ := strings.Cut(line, " ")
+	return cmd, strings.TrimSpace(arg), true
+}
+
+// inspect runs the commands which can be used before and after the step is run
+func (d *debugger) inspect(s *venom.DebugStep, cmd, arg string) {
+	switch cmd {
+	case "":
+	case "vars":
+		d.printVars(s.Vars, arg)
+	case "step":
+		d.printStep(s)
+	case "results":
+		if len(s.Results) == 0 {
+			fmt.Fprintln(d.out, "no previous step")
+		}
+		for i := range s.Results {
+			d.printResult(&s.Results[i])
+		}
+	case "result":
+		if s.Result != nil {
+			d.printResult(s.Result)
+			d.printVars(s.Result.ComputedVars, "result.")
+		}
+	case "quit", "q":
+		d.quit()
+	case "help", "h":
+		fmt.Fprintln(d.out, help)
+	default:
+		fmt.Fprintf(d.out, "unknown command %q\n%s\n", cmd, help)
+	}
+}
+
+func (d *debugger) printStep(s *venom.DebugStep) {
+	for _, l := range strings.Split(strings.TrimRight(s.Content, "\n"), "\n") {
+		fmt.Fprintf(d.out, "  %s\n", l)
+	}
+}
+
+func (d *debugger) printVars(vars venom.H, prefix string) {
+	keys := make([]string, 0, len(vars))
+	for k := range vars {
+		lk := strings.ToLower(k)
+		if strings.HasPrefix(k, prefix) && !strings.HasSuffix(lk, "__type__") && !strings.HasSuffix(lk, "__len__") {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(d.out, "  %s: %v\n", k, vars[k])
+	}
+}
+
+func (d *debugger) printResult(r *venom.TestStepResult) {
+	status := venom.Green(r.Status)
+	if r.Status == venom.StatusFail {
+		status = venom.Red(r.Status)
+	}
+	fmt.Fprintf(d.out, "  #%d %s %s (%.3fs)\n", r.Number, r.Name, status, r.Duration)
+	for _, e := range r.Errors {
+		fmt.Fprintf(d.out, "    %s\n", venom.Yellow(e.Value))
+	}
+	for _, w := range r.Warnings {
+		fmt.Fprintf(d.out, "    [warn] %s\n", w.Value)
+	}
+}
@@ -0,0 +1,145 @@
+package executors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/venom"
+	venomexecutors "github.com/ovh/venom/executors"
+)
+
+var (
+	format string
+	libDir string
+)
+
+func init() {
+	Cmd.PersistentFlags().StringVar(&format, "format", "text", "--format: text, json")
+	Cmd.PersistentFlags().StringVar(&libDir, "lib-dir", "", "Lib Directory: can contain user executors. example:/etc/venom/lib:$HOME/venom.d/lib")
+	Cmd.AddCommand(listCmd)
+	Cmd.AddCommand(describeCmd)
+}
+
+// Cmd executors
+var Cmd = &cobra.Command{
+	Use:   "executors",
+	Short: "List and describe executors",
+}
+
+var listCmd = &cobra.Command{
+	Use:     "list",
+	Short:   "List builtin executors and user executors",
+	Example: `  venom executors list --lib-dir lib`,
+	Run: func(cmd *cobra.Command, args []string) {
+		descriptions := newVenom().DescribeExecutors(".")
+		if format == "json" {
+			printJSON(descriptions)
+			return
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NAME\tKIND\tDESCRIPTION")
+		for _, d := range descriptions {
+			description := d.Description
+			if d.Kind == venom.ExecutorKindUser {
+				description = d.Filename
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\n", d.Name, d.Kind, description)
+		}
+		w.Flush() //nolint
+	},
+}
+
+var describeCmd = &cobra.Command{
+	Use:   "describe <name>",
+	Short: "Describe the inputs, outputs and default assertions of an executor",
+	Example: `  venom executors describe http
+  venom executors describe myexecutor --lib-dir lib --format json`,
+	Args: cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		d, err := newVenom().DescribeExecutor(args[0], ".")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			venom.OSExit(2)
+		}
+		if format == "json" {
+			printJSON(d)
+			return
+		}
+		printDescription(os.Stdout, d)
+	},
+}
+
+func newVenom() *venom.Venom {
+	if format != "text" && format != "json" {
+		fmt.Fprintf(os.Stderr, "invalid format %q: must be text or json\n", format)
+		venom.OSExit(2)
+	}
+	v := venom.New()
+	for name, executorFunc := range venomexecutors.Registry {
+		v.RegisterExecutorBuiltin(name, executorFunc())
+	}
+	v.LibDir = libDir
+	if libDir == "" {
+		v.LibDir = os.Getenv("VENOM_LIB_DIR")
+	}
+	return v
+}
+
+func printJSON(i interface{}) {
+	btes, err := json.MarshalIndent(i, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		venom.OSExit(2)
+	}
+	fmt.Fprintln(os.Stdout, string(btes))
+}
+
+func printDescription(out io.Writer, d *venom.ExecutorDescription) {
+	fmt.Fprintf(out, "%s (%s)\n", venom.Cyan(d.Name), d.Kind)
+	if d.Description != "" {
+		fmt.Fprintln(out, d.Description)
+	}
+	if d.Filename != "" {
+		fmt.Fprintf(out, "Defined in %s\n", d.Filename)
+	}
+
+	printFields(out, "Inputs", d.Inputs)
+	printFields(out, "Outputs", d.Outputs)
+
+	if len(d.DefaultAssertions) > 0 {
+		fmt.Fprintf(out, "\nDefault assertions:\n")
+		for _, a := range d.DefaultAssertions {
+			fmt.Fprintf(out, "  - %v\n", a)
+		}
+	}
+}
+
+func printFields(out io.Writer, title string, fields []venom.ExecutorField) {
+	fmt.Fprintf(out, "\n%s:\n", title)
+	if len(fields) == 0 {
+		fmt.Fprintln(out, "  none")
+		return
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "  NAME\tTYPE\tDEFAULT\tDESCRIPTION")
+	for _, f := range fields {
+		name := f.Name
+		if len(f.Aliases) > 0 {
+			name += " (" + strings.Join(f.Aliases, ", ") + ")"
+		}
+		var def string
+		if f.Default != nil {
+			btes, _ := json.Marshal(f.Default)
+			def = string(btes)
+		}
+		fmt.Fprintf(w, "  %s\t%s\t%s\t%s\n", name, f.Type, def, f.Description)
+	}
+	w.Flush() //nolint
+}
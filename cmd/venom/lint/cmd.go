@@ -0,0 +1,93 @@
+package lint
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/venom"
+	"github.com/ovh/venom/executors"
+)
+
+var (
+	format string
+	libDir string
+)
+
+func init() {
+	Cmd.Flags().StringVar(&format, "format", "text", "--format: text, json")
+	Cmd.Flags().StringVar(&libDir, "lib-dir", "", "Lib Directory: can contain user executors. example:/etc/venom/lib:$HOME/venom.d/lib")
+}
+
+// Cmd lint
+var Cmd = &cobra.Command{
+	Use:   "lint",
+	Short: "Check testsuites without running them",
+	Example: `  Check all testsuites containing in files ending with *.yml or *.yaml: venom lint
+  Check a single testsuite: venom lint mytestfile.yml
+  Check testsuites and print issues in JSON format: venom lint tests/ --format=json`,
+	Long: `lint checks testsuites statically: executor types and attributes, assertions, range, skip and vars blocks, unused and undefined variables.
+Exit code is 2 if an error is found, warnings don't change the exit code.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args
+		if len(path) == 0 {
+			path = []string{"."}
+		}
+		if format != "text" && format != "json" {
+			fmt.Fprintf(os.Stderr, "invalid format %q: must be text or json\n", format)
+			venom.OSExit(2)
+		}
+
+		v := venom.New()
+		for name, executorFunc := range executors.Registry {
+			v.RegisterExecutorBuiltin(name, executorFunc())
+		}
+		v.LibDir = libDir
+		if libDir == "" {
+			v.LibDir = os.Getenv("VENOM_LIB_DIR")
+		}
+
+		issues, err := v.Lint(context.Background(), path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			venom.OSExit(2)
+		}
+
+		var nbErrors int
+		for _, i := range issues {
+			if i.Severity == venom.SeverityError {
+				nbErrors++
+			}
+		}
+
+		if format == "json" {
+			if issues == nil {
+				issues = []venom.LintIssue{}
+			}
+			btes, err := json.MarshalIndent(issues, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				venom.OSExit(2)
+			}
+			fmt.Fprintln(os.Stdout, string(btes))
+		} else {
+			for _, i := range issues {
+				if i.Severity == venom.SeverityError {
+					fmt.Fprintln(os.Stdout, venom.Red(i.String()))
+				} else {
+					fmt.Fprintln(os.Stdout, venom.Yellow(i.String()))
+				}
+			}
+			fmt.Fprintf(os.Stdout, "%d error(s), %d warning(s)\n", nbErrors, len(issues)-nbErrors)
+		}
+
+		if nbErrors > 0 {
+			venom.OSExit(2)
+		}
+		venom.OSExit(0)
+		return nil
+	},
+}
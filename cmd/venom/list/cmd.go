@@ -0,0 +1,103 @@
+package list
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/venom"
+	"github.com/ovh/venom/executors"
+)
+
+var (
+	format string
+	libDir string
+)
+
+func init() {
+	Cmd.Flags().StringVar(&format, "format", "table", "--format: table, json")
+	Cmd.Flags().StringVar(&libDir, "lib-dir", "", "Lib Directory: can contain user executors. example:/etc/venom/lib:$HOME/venom.d/lib")
+}
+
+// Cmd list
+var Cmd = &cobra.Command{
+	Use:   "list",
+	Short: "List testsuites, testcases and executors",
+	Example: `  List all testsuites containing in files ending with *.yml or *.yaml: venom list
+  List the testsuites of a directory in JSON format: venom list tests/ --format=json`,
+	Long: `list prints the testsuites selected by a path, their testcases with the number of steps and the executors they use,
+and the user executors available for them. Nothing is run.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args
+		if len(path) == 0 {
+			path = []string{"."}
+		}
+		if format != "table" && format != "json" {
+			fmt.Fprintf(os.Stderr, "invalid format %q: must be table or json\n", format)
+			venom.OSExit(2)
+		}
+
+		v := venom.New()
+		for name, executorFunc := range executors.Registry {
+			v.RegisterExecutorBuiltin(name, executorFunc())
+		}
+		v.LibDir = libDir
+		if libDir == "" {
+			v.LibDir = os.Getenv("VENOM_LIB_DIR")
+		}
+
+		inventory, err := v.List(context.Background(), path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			venom.OSExit(2)
+		}
+
+		if format == "json" {
+			btes, err := json.MarshalIndent(inventory, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				venom.OSExit(2)
+			}
+			fmt.Fprintln(os.Stdout, string(btes))
+			return nil
+		}
+		printTable(os.Stdout, inventory)
+		return nil
+	},
+}
+
+func printTable(out io.Writer, inventory *venom.Inventory) {
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TESTSUITE\tTESTCASE\tID\tTAGS\tSTEPS\tEXECUTORS")
+	for _, ts := range inventory.TestSuites {
+		if ts.Error != "" {
+			fmt.Fprintf(w, "%s\t%s\t\t\t\t\n", ts.Filename, venom.Red("error: "+ts.Error))
+			continue
+		}
+		for _, tc := range ts.TestCases {
+			executors := make([]string, len(tc.Executors))
+			for i, e := range tc.Executors {
+				executors[i] = e.String()
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\n", ts.Filename, tc.Name, tc.ID, strings.Join(tc.Tags, ", "), tc.Steps, strings.Join(executors, ", "))
+		}
+	}
+	w.Flush() //nolint
+
+	if len(inventory.UserExecutors) == 0 {
+		return
+	}
+	fmt.Fprintln(out)
+	w = tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "USER EXECUTOR\tFILE\tINPUTS\tOUTPUTS")
+	for _, ux := range inventory.UserExecutors {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", ux.Name, ux.Filename, strings.Join(ux.Inputs, ", "), strings.Join(ux.Outputs, ", "))
+	}
+	w.Flush() //nolint
+}
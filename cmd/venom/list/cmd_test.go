@@ -0,0 +1,31 @@
+package list
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/ovh/venom"
+)
+
+func TestPrintTable(t *testing.T) {
+	inventory := &venom.Inventory{
+		TestSuites: []venom.TestSuiteInventory{{
+			Filename: "tests/http.yml",
+			TestCases: []venom.TestCaseInventory{
+				{Name: "get http", ID: "http-1", Tags: []string{"smoke", "http"}, Steps: 1, Executors: []venom.ExecutorUsage{{Name: "http", Kind: venom.ExecutorKindBuiltin}}},
+				{Name: "post http", Steps: 2},
+			},
+		}},
+	}
+	var out bytes.Buffer
+	printTable(&out, inventory)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Equal(t, []string{
+		"TESTSUITE       TESTCASE   ID      TAGS         STEPS  EXECUTORS",
+		"tests/http.yml  get http   http-1  smoke, http  1      http (builtin)",
+		"tests/http.yml  post http                       2",
+	}, lines)
+}
@@ -0,0 +1,16 @@
+package report
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func init() {
+	Cmd.AddCommand(mergeCmd)
+	Cmd.AddCommand(compareCmd)
+}
+
+// Cmd report
+var Cmd = &cobra.Command{
+	Use:   "report",
+	Short: "Work on the reports of previous runs",
+}
@@ -0,0 +1,154 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/venom"
+)
+
+var (
+	compareFormat           string
+	compareThreshold        float64
+	compareMinDuration      float64
+	compareFailOnRegression bool
+)
+
+func init() {
+	compareCmd.Flags().StringVar(&compareFormat, "format", "console", "--format: console, markdown, json")
+	compareCmd.Flags().Float64Var(&compareThreshold, "threshold", 20, "Increase of a duration, in percent, above which it is a regression")
+	compareCmd.Flags().Float64Var(&compareMinDuration, "min-duration", 0.1, "Duration, in seconds, below which the durations are not compared")
+	compareCmd.Flags().BoolVar(&compareFailOnRegression, "fail-on-regression", false, "Exit with code 2 if testcases newly fail or if durations regressed")
+}
+
+var compareCmd = &cobra.Command{
+	Use:   "compare <baseline.json> <current.json>",
+	Short: "Compare two runs and flag regressions",
+	Long: `Compare the JSON report of a current run with the JSON report of a baseline run: testcases which newly fail or
+newly pass, testcases which appeared or disappeared, and testsuites, testcases and steps which are slower than the threshold.
+Each report can be a glob pattern matching several JSON reports, which are merged.`,
+	Example: `  venom report compare baseline.json current.json
+  venom report compare 'baseline/*.json' 'results/*.json' --threshold 50 --format markdown --fail-on-regression`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		if compareFormat != "console" && compareFormat != "markdown" && compareFormat != "json" {
+			fmt.Fprintf(os.Stderr, "invalid format %q: must be console, markdown or json\n", compareFormat)
+			venom.OSExit(2)
+		}
+
+		var runs []venom.Tests
+		for _, arg := range args {
+			reports, err := venom.ReadReports(arg)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				venom.OSExit(2)
+			}
+			runs = append(runs, venom.MergeReports(reports...))
+		}
+
+		c := venom.CompareReports(runs[0], runs[1], venom.CompareOptions{
+			Threshold:   compareThreshold,
+			MinDuration: compareMinDuration,
+		})
+
+		switch compareFormat {
+		case "json":
+			btes, err := json.MarshalIndent(c, "", "  ")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				venom.OSExit(2)
+			}
+			fmt.Fprintln(os.Stdout, string(btes))
+		case "markdown":
+			printComparisonMarkdown(os.Stdout, c)
+		default:
+			printComparison(os.Stdout, c)
+		}
+
+		if compareFailOnRegression && c.HasRegressions() {
+			venom.OSExit(2)
+		}
+	},
+}
+
+func printComparison(w io.Writer, c venom.Comparison) {
+	printTestCases := func(title string, tcs []venom.ComparedTestCase, color func(a ...interface{}) string) {
+		if len(tcs) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "%s (%d)\n", title, len(tcs))
+		for _, tc := range tcs {
+			fmt.Fprintf(w, "  %s %s\n", color("•"), comparedTestCaseName(tc))
+		}
+	}
+	printTestCases("New failures", c.NewFailures, venom.Red)
+	printTestCases("New passes", c.NewPasses, venom.Green)
+	printTestCases("Appeared", c.Appeared, venom.Cyan)
+	printTestCases("Disappeared", c.Disappeared, venom.Gray)
+	if len(c.DurationRegressions) > 0 {
+		fmt.Fprintf(w, "Duration regressions (%d)\n", len(c.DurationRegressions))
+		for _, r := range c.DurationRegressions {
+			fmt.Fprintf(w, "  %s %s %s: %.2fs -> %.2fs (%s)\n", venom.Yellow("•"), r.Level, r.Name(), r.Baseline, r.Current, venom.Yellow(fmt.Sprintf("+%.0f%%", r.Increase)))
+		}
+	}
+
+	if c.HasRegressions() {
+		fmt.Fprintf(w, "%s %d new failure(s), %d duration regression(s)\n", venom.Red("regressions:"), len(c.NewFailures), len(c.DurationRegressions))
+		return
+	}
+	fmt.Fprintf(w, "%s\n", venom.Green("no regression"))
+}
+
+func printComparisonMarkdown(w io.Writer, c venom.Comparison) {
+	fmt.Fprintf(w, "## Comparison with the baseline\n\n")
+	if c.HasRegressions() {
+		fmt.Fprintf(w, ":x: %d new failure(s), %d duration regression(s)\n", len(c.NewFailures), len(c.DurationRegressions))
+	} else {
+		fmt.Fprintf(w, ":white_check_mark: no regression\n")
+	}
+
+	printTestCases := func(title string, tcs []venom.ComparedTestCase) {
+		if len(tcs) == 0 {
+			return
+		}
+		fmt.Fprintf(w, "\n### %s (%d)\n\n", title, len(tcs))
+		fmt.Fprintf(w, "| Testsuite | Testcase | Baseline | Current |\n")
+		fmt.Fprintf(w, "|---|---|---|---|\n")
+		for _, tc := range tcs {
+			fmt.Fprintf(w, "| %s | %s | %s | %s |\n", markdownEscape(tc.TestSuite), markdownEscape(tc.TestCase), orDash(string(tc.Baseline)), orDash(string(tc.Current)))
+		}
+	}
+	printTestCases("New failures", c.NewFailures)
+	printTestCases("New passes", c.NewPasses)
+	printTestCases("Appeared", c.Appeared)
+	printTestCases("Disappeared", c.Disappeared)
+
+	if len(c.DurationRegressions) > 0 {
+		fmt.Fprintf(w, "\n### Duration regressions (%d)\n\n", len(c.DurationRegressions))
+		fmt.Fprintf(w, "| Level | Name | Baseline | Current | Increase |\n")
+		fmt.Fprintf(w, "|---|---|---|---|---|\n")
+		for _, r := range c.DurationRegressions {
+			fmt.Fprintf(w, "| %s | %s | %.2fs | %.2fs | +%.0f%% |\n", r.Level, markdownEscape(r.Name()), r.Baseline, r.Current, r.Increase)
+		}
+	}
+}
+
+func comparedTestCaseName(tc venom.ComparedTestCase) string {
+	return tc.TestSuite + " > " + tc.TestCase
+}
+
+func markdownEscape(s string) string {
+	return strings.ReplaceAll(s, "|", "\\|")
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
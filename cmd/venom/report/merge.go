@@ -0,0 +1,80 @@
+package report
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/venom"
+)
+
+var (
+	mergeFormat    string
+	mergeOutputDir string
+)
+
+func init() {
+	mergeCmd.Flags().StringVar(&mergeFormat, "format", "xml", "--format: comma separated list of json, tap, xml, yaml, html, ctrf, allure, markdown")
+	mergeCmd.Flags().StringVar(&mergeOutputDir, "output-dir", ".", "Output Directory: create the merged results files inside this directory")
+}
+
+var mergeCmd = &cobra.Command{
+	Use:   "merge <json reports...>",
+	Short: "Merge JSON reports into a single report",
+	Long: `Merge the JSON reports written by venom run --format json, for instance by several shards, into a single report.
+The counters, durations and statuses are computed again. When a testsuite is found in several reports, the last run is kept.
+The exit code is 2 if a testsuite of the merged report failed.`,
+	Example: `  venom report merge shard-*/test_results_*.json --format xml,html --output-dir results`,
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		formats, err := venom.ParseFormats(mergeFormat)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			venom.OSExit(2)
+		}
+
+		reports, err := venom.ReadReports(args...)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			venom.OSExit(2)
+		}
+		merged := venom.MergeReports(reports...)
+
+		if err := os.MkdirAll(mergeOutputDir, os.FileMode(0755)); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to create output dir: %v\n", err)
+			venom.OSExit(2)
+		}
+		for _, f := range formats {
+			if f == "allure" {
+				dir := filepath.Join(mergeOutputDir, venom.AllureResultsDir)
+				if err := venom.WriteAllureResults(dir, &merged); err != nil {
+					fmt.Fprintf(os.Stderr, "%v\n", err)
+					venom.OSExit(2)
+				}
+				fmt.Fprintf(os.Stdout, "Writing allure results in %s\n", dir)
+				continue
+			}
+			data, err := venom.FormatTests(&merged, f)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
+				venom.OSExit(2)
+			}
+			filename := filepath.Join(mergeOutputDir, "test_results."+venom.FormatExtension(f))
+			if err := os.WriteFile(filename, data, 0600); err != nil {
+				fmt.Fprintf(os.Stderr, "Error while creating file %s: %v\n", filename, err)
+				venom.OSExit(2)
+			}
+			fmt.Fprintf(os.Stdout, "Writing file %s\n", filename)
+		}
+
+		fmt.Fprintf(os.Stdout, "%d report(s) merged: %d testsuite(s), %d passed, %d failed, %d skipped\n",
+			len(reports), len(merged.TestSuites), merged.NbTestsuitesPass, merged.NbTestsuitesFail, merged.NbTestsuitesSkip)
+		if merged.Status == venom.StatusFail {
+			fmt.Fprintf(os.Stdout, "final status: %v\n", venom.Red(merged.Status))
+			venom.OSExit(2)
+		}
+		fmt.Fprintf(os.Stdout, "final status: %v\n", venom.Green(merged.Status))
+	},
+}
@@ -3,7 +3,14 @@ package root
 import (
 	"github.com/spf13/cobra"
 
+	"github.com/ovh/venom/cmd/venom/debug"
+	"github.com/ovh/venom/cmd/venom/executors"
+	"github.com/ovh/venom/cmd/venom/lint"
+	"github.com/ovh/venom/cmd/venom/list"
+	"github.com/ovh/venom/cmd/venom/report"
 	"github.com/ovh/venom/cmd/venom/run"
+	"github.com/ovh/venom/cmd/venom/schema"
+	"github.com/ovh/venom/cmd/venom/serve"
 	"github.com/ovh/venom/cmd/venom/update"
 	"github.com/ovh/venom/cmd/venom/version"
 )
@@ -21,6 +28,13 @@ func New() *cobra.Command {
 //AddCommands adds child commands to the root command rootCmd.
 func addCommands(cmd *cobra.Command) {
 	cmd.AddCommand(run.Cmd)
+	cmd.AddCommand(debug.Cmd)
+	cmd.AddCommand(lint.Cmd)
+	cmd.AddCommand(executors.Cmd)
+	cmd.AddCommand(list.Cmd)
+	cmd.AddCommand(schema.Cmd)
+	cmd.AddCommand(report.Cmd)
+	cmd.AddCommand(serve.Cmd)
 	cmd.AddCommand(version.Cmd)
 	cmd.AddCommand(update.Cmd)
 }
@@ -5,10 +5,13 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"os/signal"
 	"path/filepath"
 	"runtime/pprof"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/mitchellh/go-homedir"
 	"github.com/pkg/errors"
@@ -22,9 +25,6 @@ import (
 )
 
 var (
-	path []string
-	v    *venom.Venom
-
 	variables     []string
 	secrets       []string
 	format        string = "xml" // Set the default value for formatFlag
@@ -32,8 +32,22 @@ var (
 	outputDir     string
 	libDir        string
 	htmlReport    bool
+	htmlHistory   int
+	junitPerStep  bool
 	stopOnFailure bool
 	verbose       int = 0 // Set the default value for verboseFlag
+	watch         bool
+	dryRun        bool
+	shard         string
+	shardTimings  []string
+	env           string
+	eventStream   string
+	summaryFile   string
+	outputMode    string
+	otlpEndpoint  string
+	otlpFile      string
+	metricsFile   string
+	pushgateway   string
 
 	variablesFlag     *[]string
 	formatFlag        *string
@@ -42,27 +56,59 @@ var (
 	libDirFlag        *string
 	stopOnFailureFlag *bool
 	htmlReportFlag    *bool
+	htmlHistoryFlag   *int
+	junitPerStepFlag  *bool
 	verboseFlag       *int
+	watchFlag         *bool
+	dryRunFlag        *bool
+	shardFlag         *string
+	shardTimingsFlag  *[]string
+	envFlag           *string
+	eventStreamFlag   *string
+	summaryFileFlag   *string
+	outputModeFlag    *string
+	otlpEndpointFlag  *string
+	otlpFileFlag      *string
+	metricsFileFlag   *string
+	pushgatewayFlag   *string
 )
 
 func init() {
-	formatFlag = Cmd.Flags().String("format", "xml", "--format:json, tap, xml, yaml")
+	formatFlag = Cmd.Flags().String("format", "xml", "--format:json, tap, xml, yaml, ctrf, allure, markdown, or a comma separated list of them: --format xml,json")
 	stopOnFailureFlag = Cmd.Flags().Bool("stop-on-failure", false, "Stop running Test Suite on first Test Case failure")
 	htmlReportFlag = Cmd.Flags().Bool("html-report", false, "Generate HTML Report")
+	htmlHistoryFlag = Cmd.Flags().Int("html-history", 0, "Show the last runs, read from the history directory of the output directory, in the HTML report, and add the run to the history")
+	junitPerStepFlag = Cmd.Flags().Bool("junit-per-step", false, "Write a JUnit testcase per step in the xml reports, instead of per testcase")
 	verboseFlag = Cmd.Flags().CountP("verbose", "v", "verbose. -v (INFO level in venom.log file), -vv to very verbose (DEBUG level) and -vvv to very verbose with CPU Profiling")
 	varFilesFlag = Cmd.Flags().StringSlice("var-from-file", []string{""}, "--var-from-file filename.yaml --var-from-file filename2.yaml: yaml, must contains a dictionary")
 	variablesFlag = Cmd.Flags().StringArray("var", nil, "--var cds='cds -f config.json' --var cds2='cds -f config.json'")
+	watchFlag = Cmd.Flags().Bool("watch", false, "Watch testsuites, user executors and var files, and run the affected testsuites again when they change")
+	dryRunFlag = Cmd.Flags().Bool("dry-run", false, "Render the interpolated steps of the testsuites without running them")
+	shardFlag = Cmd.Flags().String("shard", "", "Run only a shard of the testsuites, given as index/total: --shard 3/8")
+	shardTimingsFlag = Cmd.Flags().StringSlice("shard-timings", nil, "JSON reports of a previous run, used to balance the shards by duration. Glob patterns are accepted")
+	envFlag = Cmd.Flags().String("env", "", "Environment of the configuration file to use: its settings override the default ones of the configuration file")
+	eventStreamFlag = Cmd.Flags().String("event-stream", "", "Write the events of the run, as newline delimited JSON, in a file or on the standard output with -")
+	summaryFileFlag = Cmd.Flags().String("summary-file", "", "Append a markdown summary of the run to a file, like $GITHUB_STEP_SUMMARY")
+	outputModeFlag = Cmd.Flags().String("output-mode", venom.OutputModePerSuite, "Write a report per testsuite and per format with per-suite, or a single report per format with aggregate")
+	otlpEndpointFlag = Cmd.Flags().String("otlp-endpoint", "", "Send the traces of the run to an OpenTelemetry OTLP/HTTP endpoint: --otlp-endpoint http://localhost:4318")
+	otlpFileFlag = Cmd.Flags().String("otlp-file", "", "Write the traces of the run to a file, in the OpenTelemetry OTLP JSON format")
+	metricsFileFlag = Cmd.Flags().String("metrics-file", "", "Write the Prometheus metrics of the run to a file, for the textfile collector of the node exporter: --metrics-file /var/lib/node_exporter/venom.prom")
+	pushgatewayFlag = Cmd.Flags().String("metrics-pushgateway", "", "Push the Prometheus metrics of the run to a Pushgateway: --metrics-pushgateway http://localhost:9091")
 	outputDirFlag = Cmd.PersistentFlags().String("output-dir", "", "Output Directory: create tests results file inside this directory")
 	libDirFlag = Cmd.PersistentFlags().String("lib-dir", "", "Lib Directory: can contain user executors. example:/etc/venom/lib:$HOME/venom.d/lib")
 }
 
 func initArgs(cmd *cobra.Command) {
-	// command line flags overrides the configuration file.
+	// command line flags overrides the environment of the configuration file.
+	// The environment of the configuration file overrides its default settings.
 	// Configuration file overrides the environment variables.
 	if _, err := initFromEnv(os.Environ()); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
 		venom.OSExit(2)
 	}
+	if envFlag != nil && cmd.Flags().Changed("env") {
+		env = *envFlag
+	}
 
 	if err := initFromConfigFile(); err != nil {
 		fmt.Fprintf(os.Stderr, "%v\n", err)
@@ -89,6 +135,14 @@ func initFromCommandArguments(f *pflag.Flag) {
 		if htmlReportFlag != nil {
 			htmlReport = *htmlReportFlag
 		}
+	case "html-history":
+		if htmlHistoryFlag != nil {
+			htmlHistory = *htmlHistoryFlag
+		}
+	case "junit-per-step":
+		if junitPerStepFlag != nil {
+			junitPerStep = *junitPerStepFlag
+		}
 	case "output-dir":
 		if outputDirFlag != nil {
 			outputDir = *outputDirFlag
@@ -101,6 +155,50 @@ func initFromCommandArguments(f *pflag.Flag) {
 		if verboseFlag != nil {
 			verbose = *verboseFlag
 		}
+	case "watch":
+		if watchFlag != nil {
+			watch = *watchFlag
+		}
+	case "dry-run":
+		if dryRunFlag != nil {
+			dryRun = *dryRunFlag
+		}
+	case "shard":
+		if shardFlag != nil {
+			shard = *shardFlag
+		}
+	case "shard-timings":
+		if shardTimingsFlag != nil {
+			shardTimings = *shardTimingsFlag
+		}
+	case "event-stream":
+		if eventStreamFlag != nil {
+			eventStream = *eventStreamFlag
+		}
+	case "output-mode":
+		if outputModeFlag != nil {
+			outputMode = *outputModeFlag
+		}
+	case "summary-file":
+		if summaryFileFlag != nil {
+			summaryFile = *summaryFileFlag
+		}
+	case "otlp-endpoint":
+		if otlpEndpointFlag != nil {
+			otlpEndpoint = *otlpEndpointFlag
+		}
+	case "otlp-file":
+		if otlpFileFlag != nil {
+			otlpFile = *otlpFileFlag
+		}
+	case "metrics-file":
+		if metricsFileFlag != nil {
+			metricsFile = *metricsFileFlag
+		}
+	case "metrics-pushgateway":
+		if pushgatewayFlag != nil {
+			pushgateway = *pushgatewayFlag
+		}
 	case "var-from-file":
 		if varFilesFlag != nil {
 			for _, varFile := range *varFilesFlag {
@@ -148,6 +246,9 @@ func initFromConfigFile() error {
 		defer fi.Close()
 		return initFromReaderConfigFile(fi)
 	}
+	if env != "" {
+		return fmt.Errorf("environment %q not found: no .venomrc configuration file", env)
+	}
 	return nil
 }
 
@@ -155,12 +256,22 @@ type ConfigFileData struct {
 	Format         *string   `json:"format,omitempty" yaml:"format,omitempty"`
 	LibDir         *string   `json:"lib_dir,omitempty" yaml:"lib_dir,omitempty"`
 	OutputDir      *string   `json:"output_dir,omitempty" yaml:"output_dir,omitempty"`
+	OutputMode     *string   `json:"output_mode,omitempty" yaml:"output_mode,omitempty"`
 	StopOnFailure  *bool     `json:"stop_on_failure,omitempty" yaml:"stop_on_failure,omitempty"`
 	HtmlReport     *bool     `json:"html_report,omitempty" yaml:"html_report,omitempty"`
+	HtmlHistory    *int      `json:"html_history,omitempty" yaml:"html_history,omitempty"`
+	JUnitPerStep   *bool     `json:"junit_per_step,omitempty" yaml:"junit_per_step,omitempty"`
+	SummaryFile    *string   `json:"summary_file,omitempty" yaml:"summary_file,omitempty"`
+	OTLPEndpoint   *string   `json:"otlp_endpoint,omitempty" yaml:"otlp_endpoint,omitempty"`
+	OTLPFile       *string   `json:"otlp_file,omitempty" yaml:"otlp_file,omitempty"`
+	MetricsFile    *string   `json:"metrics_file,omitempty" yaml:"metrics_file,omitempty"`
+	Pushgateway    *string   `json:"metrics_pushgateway,omitempty" yaml:"metrics_pushgateway,omitempty"`
 	Variables      *[]string `json:"variables,omitempty" yaml:"variables,omitempty"`
 	Secrets        *[]string `json:"secrets,omitempty" yaml:"secrets,omitempty"`
 	VariablesFiles *[]string `json:"variables_files,omitempty" yaml:"variables_files,omitempty"`
 	Verbosity      *int      `json:"verbosity,omitempty" yaml:"verbosity,omitempty"`
+	// Environments override the settings above when they are selected with --env
+	Environments map[string]ConfigFileData `json:"environments,omitempty" yaml:"environments,omitempty"`
 }
 
 // Configuration file overrides the environment variables.
@@ -176,6 +287,28 @@ func initFromReaderConfigFile(reader io.Reader) error {
 		return err
 	}
 
+	initFromConfigFileData(configFileData)
+	if env == "" {
+		return nil
+	}
+	envData, ok := configFileData.Environments[env]
+	if !ok {
+		names := make([]string, 0, len(configFileData.Environments))
+		for name := range configFileData.Environments {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return fmt.Errorf("environment %q not found in the configuration file, available environments: %s", env, strings.Join(names, ", "))
+	}
+	if len(envData.Environments) > 0 {
+		return fmt.Errorf("invalid environment %q in the configuration file: environments can't be nested", env)
+	}
+	initFromConfigFileData(envData)
+	return nil
+}
+
+// initFromConfigFileData applies the settings of the configuration file, or of one of its environments
+func initFromConfigFileData(configFileData ConfigFileData) {
 	if configFileData.Format != nil {
 		format = *configFileData.Format
 	}
@@ -185,19 +318,47 @@ func initFromReaderConfigFile(reader io.Reader) error {
 	if configFileData.OutputDir != nil {
 		outputDir = *configFileData.OutputDir
 	}
+	if configFileData.OutputMode != nil {
+		outputMode = *configFileData.OutputMode
+	}
 	if configFileData.StopOnFailure != nil {
 		stopOnFailure = *configFileData.StopOnFailure
 	}
 	if configFileData.HtmlReport != nil {
 		htmlReport = *configFileData.HtmlReport
 	}
+	if configFileData.HtmlHistory != nil {
+		htmlHistory = *configFileData.HtmlHistory
+	}
+	if configFileData.JUnitPerStep != nil {
+		junitPerStep = *configFileData.JUnitPerStep
+	}
+	if configFileData.SummaryFile != nil {
+		summaryFile = *configFileData.SummaryFile
+	}
+	if configFileData.OTLPEndpoint != nil {
+		otlpEndpoint = *configFileData.OTLPEndpoint
+	}
+	if configFileData.OTLPFile != nil {
+		otlpFile = *configFileData.OTLPFile
+	}
+	if configFileData.MetricsFile != nil {
+		metricsFile = *configFileData.MetricsFile
+	}
+	if configFileData.Pushgateway != nil {
+		pushgateway = *configFileData.Pushgateway
+	}
 	if configFileData.Variables != nil {
 		for _, varFromFile := range *configFileData.Variables {
 			variables = mergeVariables(varFromFile, variables)
 		}
 	}
 	if configFileData.Secrets != nil {
-		secrets = append(secrets, *configFileData.Secrets...)
+		for _, secret := range *configFileData.Secrets {
+			if !isInArray(secret, secrets) {
+				secrets = append(secrets, secret)
+			}
+		}
 	}
 	if configFileData.VariablesFiles != nil {
 		for _, varFile := range *configFileData.VariablesFiles {
@@ -209,8 +370,6 @@ func initFromReaderConfigFile(reader io.Reader) error {
 	if configFileData.Verbosity != nil {
 		verbose = *configFileData.Verbosity
 	}
-
-	return nil
 }
 
 func mergeVariables(varToMerge string, existingVariables []string) []string {
@@ -264,12 +423,56 @@ func initFromEnv(environ []string) ([]string, error) {
 			return nil, fmt.Errorf("invalid value for VENOM_HTML_REPORT")
 		}
 	}
+	if os.Getenv("VENOM_HTML_HISTORY") != "" {
+		v, err := strconv.Atoi(os.Getenv("VENOM_HTML_HISTORY"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for VENOM_HTML_HISTORY")
+		}
+		htmlHistory = v
+	}
+	if os.Getenv("VENOM_JUNIT_PER_STEP") != "" {
+		var err error
+		junitPerStep, err = strconv.ParseBool(os.Getenv("VENOM_JUNIT_PER_STEP"))
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for VENOM_JUNIT_PER_STEP")
+		}
+	}
 	if os.Getenv("VENOM_LIB_DIR") != "" {
 		libDir = os.Getenv("VENOM_LIB_DIR")
 	}
 	if os.Getenv("VENOM_OUTPUT_DIR") != "" {
 		outputDir = os.Getenv("VENOM_OUTPUT_DIR")
 	}
+	if os.Getenv("VENOM_OUTPUT_MODE") != "" {
+		outputMode = os.Getenv("VENOM_OUTPUT_MODE")
+	}
+	if os.Getenv("VENOM_SHARD") != "" {
+		shard = os.Getenv("VENOM_SHARD")
+	}
+	if os.Getenv("VENOM_SHARD_TIMINGS") != "" {
+		shardTimings = strings.Split(os.Getenv("VENOM_SHARD_TIMINGS"), " ")
+	}
+	if os.Getenv("VENOM_ENV") != "" {
+		env = os.Getenv("VENOM_ENV")
+	}
+	if os.Getenv("VENOM_EVENT_STREAM") != "" {
+		eventStream = os.Getenv("VENOM_EVENT_STREAM")
+	}
+	if os.Getenv("VENOM_SUMMARY_FILE") != "" {
+		summaryFile = os.Getenv("VENOM_SUMMARY_FILE")
+	}
+	if os.Getenv("VENOM_OTLP_ENDPOINT") != "" {
+		otlpEndpoint = os.Getenv("VENOM_OTLP_ENDPOINT")
+	}
+	if os.Getenv("VENOM_OTLP_FILE") != "" {
+		otlpFile = os.Getenv("VENOM_OTLP_FILE")
+	}
+	if os.Getenv("VENOM_METRICS_FILE") != "" {
+		metricsFile = os.Getenv("VENOM_METRICS_FILE")
+	}
+	if os.Getenv("VENOM_METRICS_PUSHGATEWAY") != "" {
+		pushgateway = os.Getenv("VENOM_METRICS_PUSHGATEWAY")
+	}
 	if os.Getenv("VENOM_VERBOSE") != "" {
 		v, err := strconv.ParseInt(os.Getenv("VENOM_VERBOSE"), 10, 64)
 		if err != nil {
@@ -297,13 +500,24 @@ func initFromEnv(environ []string) ([]string, error) {
 }
 
 func displayArg(ctx context.Context) {
+	venom.Debug(ctx, "option env=%v", env)
 	venom.Debug(ctx, "option format=%v", format)
 	venom.Debug(ctx, "option libDir=%v", libDir)
 	venom.Debug(ctx, "option outputDir=%v", outputDir)
+	venom.Debug(ctx, "option outputMode=%v", outputMode)
 	venom.Debug(ctx, "option stopOnFailure=%v", stopOnFailure)
 	venom.Debug(ctx, "option htmlReport=%v", htmlReport)
+	venom.Debug(ctx, "option htmlHistory=%v", htmlHistory)
+	venom.Debug(ctx, "option junitPerStep=%v", junitPerStep)
 	venom.Debug(ctx, "option varFiles=%v", strings.Join(varFiles, " "))
 	venom.Debug(ctx, "option verbose=%v", verbose)
+	venom.Debug(ctx, "option shard=%v", shard)
+	venom.Debug(ctx, "option eventStream=%v", eventStream)
+	venom.Debug(ctx, "option summaryFile=%v", summaryFile)
+	venom.Debug(ctx, "option otlpEndpoint=%v", otlpEndpoint)
+	venom.Debug(ctx, "option otlpFile=%v", otlpFile)
+	venom.Debug(ctx, "option metricsFile=%v", metricsFile)
+	venom.Debug(ctx, "option pushgateway=%v", pushgateway)
 }
 
 // Cmd run
@@ -314,48 +528,72 @@ var Cmd = &cobra.Command{
   Run a single testsuite: venom run mytestfile.yml
   Run a single testsuite and export the result in JSON format in test/ folder: venom run mytestfile.yml --format=json --output-dir=test
   Run a single testsuite and export the result in XML and HTML formats in test/ folder: venom run mytestfile.yml --format=xml --output-dir=test --html-report
+  Run all testsuites and export a single report with all of them, in XML and JSON formats: venom run --format=xml,json --output-mode=aggregate --output-dir=test
   Run a single testsuite and specify a variable: venom run mytestfile.yml --var="foo=bar"
   Run a single testsuite and load all variables from a file: venom run mytestfile.yml --var-from-file variables.yaml
   Run all testsuites containing in files ending with *.yml or *.yaml with verbosity: VENOM_VERBOSE=2 venom run
+  Run a testsuite each time it changes: venom run mytestfile.yml --watch
+  Show the steps of a testsuite, as they would be run: venom run mytestfile.yml --dry-run
+  Run the testsuites with the settings of the staging environment of the .venomrc file: venom run --env staging
+  Add a summary of the run to the GitHub Actions job summary: venom run tests/ --summary-file "$GITHUB_STEP_SUMMARY"
+  Send the traces of the run to an OpenTelemetry collector: venom run tests/ --otlp-endpoint http://localhost:4318
+  Write the metrics of the run for the textfile collector of the node exporter: venom run tests/ --metrics-file /var/lib/node_exporter/venom.prom
+  Stream the events of the run to a dashboard: venom run tests/ --event-stream - | my-dashboard
+  Run the third of 8 shards of the testsuites, balanced with the durations of a previous run: venom run tests/ --shard 3/8 --shard-timings 'previous/*.json'
   
   Notice that variables initialized with -var-from-file argument can be overrided with -var argument
   
   More info: https://github.com/ovh/venom`,
 	Long: `run integration tests`,
-	PreRun: func(cmd *cobra.Command, args []string) {
-		if len(args) == 0 {
-			path = append(path, ".")
-		} else {
-			path = args[0:]
-		}
-
-		v = venom.New()
-		for name, executorFunc := range executors.Registry {
-			v.RegisterExecutorBuiltin(name, executorFunc())
-		}
-	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		initArgs(cmd)
 
-		v.OutputDir = outputDir
-		v.LibDir = libDir
-		v.OutputFormat = format
-		v.StopOnFailure = stopOnFailure
-		v.HtmlReport = htmlReport
-		v.Verbose = verbose
+		path := args
+		if len(path) == 0 {
+			path = []string{"."}
+		}
 
-		if err := v.InitLogger(); err != nil {
+		opts, err := newOptions(context.Background())
+		if err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			venom.OSExit(2)
 		}
+		if watch && opts.Shard != nil {
+			fmt.Fprintf(os.Stderr, "--shard can't be used with --watch\n")
+			venom.OSExit(2)
+		}
+		if eventStream == "-" && (watch || dryRun) {
+			fmt.Fprintf(os.Stderr, "--event-stream - can't be used with --watch or --dry-run\n")
+			venom.OSExit(2)
+		}
+		if eventStream != "" {
+			w := io.Writer(os.Stdout)
+			if eventStream != "-" {
+				f, err := os.Create(eventStream)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "unable to create the event stream: %v\n", err)
+					venom.OSExit(2)
+				}
+				defer f.Close() //nolint
+				w = f
+			}
+			opts.Reporters = append(opts.Reporters, venom.NewNDJSONReporter(w))
+		}
+
+		v, err := venom.NewWithOptions(opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			venom.OSExit(2)
+		}
+		defer v.Close() //nolint
 
-		if v.Verbose == 3 {
-			fCPU, err := os.Create(filepath.Join(v.OutputDir, "pprof_cpu_profile.prof"))
+		if verbose == 3 {
+			fCPU, err := os.Create(filepath.Join(outputDir, "pprof_cpu_profile.prof"))
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "error while create profile file %v\n", err)
 				venom.OSExit(2)
 			}
-			fMem, err := os.Create(filepath.Join(v.OutputDir, "pprof_mem_profile.prof"))
+			fMem, err := os.Create(filepath.Join(outputDir, "pprof_mem_profile.prof"))
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "error while create profile file %v\n", err)
 				venom.OSExit(2)
@@ -367,57 +605,182 @@ var Cmd = &cobra.Command{
 				defer pprof.StopCPUProfile()
 			}
 		}
+		ctx := v.WithLogger(context.Background())
 		if verbose >= 2 {
-			displayArg(context.Background())
+			displayArg(ctx)
 		}
 
-		var readers = []io.Reader{}
-		for _, f := range varFiles {
-			if f == "" {
-				continue
-			}
-			fi, err := os.Open(f)
-			if err != nil {
-				fmt.Fprintf(os.Stderr, "unable to open var-from-file %s: %v\n", f, err)
+		if watch {
+			ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+			defer stop()
+			if err := v.Watch(ctx, path, varFiles, venom.DefaultWatchInterval, runWatchIteration(v)); err != nil {
+				fmt.Fprintf(os.Stderr, "%v\n", err)
 				venom.OSExit(2)
 			}
-			defer fi.Close()
-			readers = append(readers, fi)
+			venom.OSExit(0)
+			return nil
 		}
 
-		mapvars, err := readInitialVariables(context.Background(), variables, readers, os.Environ())
+		tests, err := v.Run(ctx, path)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "%v\n", err)
 			venom.OSExit(2)
 		}
-		v.AddVariables(mapvars)
-
-		if err := v.Parse(context.Background(), path); err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
-			venom.OSExit(2)
-		}
-
-		if err := v.Process(context.Background(), path); err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
-			venom.OSExit(2)
-		}
 
-		if err := v.OutputResult(); err != nil {
-			fmt.Fprintf(os.Stderr, "%v\n", err)
-			venom.OSExit(2)
+		if dryRun {
+			if outputDir == "" {
+				if err := venom.WriteDryRun(os.Stdout, v.DryRunResult()); err != nil {
+					fmt.Fprintf(os.Stderr, "%v\n", err)
+					venom.OSExit(2)
+				}
+			}
+			if tests.Status == venom.StatusFail {
+				venom.OSExit(2)
+			}
+			venom.OSExit(0)
+			return nil
 		}
 
-		if v.Tests.Status == venom.StatusPass {
-			fmt.Fprintf(os.Stdout, "final status: %v\n", venom.Green(v.Tests.Status))
+		if tests.Status == venom.StatusPass {
+			fmt.Fprintf(opts.Output, "final status: %v\n", venom.Green(tests.Status))
 			venom.OSExit(0)
 		}
-		fmt.Fprintf(os.Stdout, "final status: %v\n", venom.Red(v.Tests.Status))
+		fmt.Fprintf(opts.Output, "final status: %v\n", venom.Red(tests.Status))
 		venom.OSExit(2)
 
 		return nil
 	},
 }
 
+// newOptions computes the options of the run from the arguments, the configuration file and the environment
+func newOptions(ctx context.Context) (venom.Options, error) {
+	opts := venom.Options{
+		Executors:     map[string]venom.Executor{},
+		LibDir:        libDir,
+		OutputDir:     outputDir,
+		OutputFormat:  format,
+		OutputMode:    outputMode,
+		HtmlReport:    htmlReport,
+		HtmlHistory:   htmlHistory,
+		JUnitPerStep:  junitPerStep,
+		SummaryFile:   summaryFile,
+		OTLPEndpoint:  otlpEndpoint,
+		OTLPFile:      otlpFile,
+		MetricsFile:   metricsFile,
+		Pushgateway:   pushgateway,
+		StopOnFailure: stopOnFailure,
+		Verbose:       verbose,
+		DryRun:        dryRun,
+		Output:        os.Stdout,
+	}
+	if dryRun || eventStream == "-" {
+		// the rendered steps or the events are written on the standard output
+		opts.Output = os.Stderr
+	}
+	for name, executorFunc := range executors.Registry {
+		opts.Executors[name] = executorFunc()
+	}
+
+	if shard != "" {
+		s, err := venom.ParseShard(shard)
+		if err != nil {
+			return opts, err
+		}
+		s.Timings, err = venom.LoadShardTimings(shardTimings...)
+		if err != nil {
+			return opts, err
+		}
+		opts.Shard = s
+	}
+
+	mapvars, err := loadInitialVariables(ctx)
+	if err != nil {
+		return opts, err
+	}
+	opts.Variables = mapvars
+	opts.Secrets = map[string]interface{}{}
+	for _, secret := range secrets {
+		if value, ok := mapvars[secret]; ok {
+			opts.Secrets[secret] = value
+		}
+	}
+	return opts, nil
+}
+
+// loadInitialVariables reads the variables from the var files, the command line and the environment
+func loadInitialVariables(ctx context.Context) (map[string]interface{}, error) {
+	return LoadVariables(ctx, variables, varFiles)
+}
+
+// LoadVariables reads the variables from var files and from name=value arguments
+func LoadVariables(ctx context.Context, variables []string, varFiles []string) (map[string]interface{}, error) {
+	var readers = []io.Reader{}
+	for _, f := range varFiles {
+		if f == "" {
+			continue
+		}
+		fi, err := os.Open(f)
+		if err != nil {
+			return nil, fmt.Errorf("unable to open var-from-file %s: %v", f, err)
+		}
+		defer fi.Close()
+		readers = append(readers, fi)
+	}
+	return readInitialVariables(ctx, variables, readers, os.Environ())
+}
+
+// runWatchIteration runs the testsuites affected by a change and prints a compact summary
+func runWatchIteration(v *venom.Venom) func(ctx context.Context, testsuites []string) {
+	return func(ctx context.Context, testsuites []string) {
+		start := time.Now()
+		fmt.Fprintf(os.Stdout, "%s\n", venom.Gray(fmt.Sprintf("[%s] running %d testsuite(s)", start.Format("15:04:05"), len(testsuites))))
+
+		v.Reset()
+		mapvars, err := loadInitialVariables(ctx)
+		if err != nil {
+			fmt.Fprintf(os.Stdout, "%s\n", venom.Red(err))
+			return
+		}
+		v.AddVariables(mapvars)
+
+		tests, err := v.Run(ctx, testsuites)
+		if err != nil {
+			fmt.Fprintf(os.Stdout, "%s\n", venom.Red(err))
+			return
+		}
+		printWatchSummary(tests, start)
+	}
+}
+
+// printWatchSummary prints a compact summary of a run in watch mode
+func printWatchSummary(tests *venom.Tests, start time.Time) {
+	var passed, failed, skipped int
+	var failures []string
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			switch tc.Status {
+			case venom.StatusPass:
+				passed++
+			case venom.StatusFail:
+				failed++
+				failures = append(failures, fmt.Sprintf("%s > %s", ts.Filename, tc.Name))
+			case venom.StatusSkip:
+				skipped++
+			}
+		}
+	}
+	summary := fmt.Sprintf("%d testcase(s): %d passed, %d failed, %d skipped in %.2fs", passed+failed+skipped, passed, failed, skipped, time.Since(start).Seconds())
+	if failed > 0 {
+		fmt.Fprintf(os.Stdout, "%s %s\n", venom.Red(venom.StatusFail), summary)
+		for _, f := range failures {
+			fmt.Fprintf(os.Stdout, "  %s %s\n", venom.Red("✗"), f)
+		}
+	} else {
+		fmt.Fprintf(os.Stdout, "%s %s\n", venom.Green(venom.StatusPass), summary)
+	}
+	fmt.Fprintf(os.Stdout, "%s\n", venom.Gray("watching for changes, press Ctrl+C to stop"))
+}
+
 func readInitialVariables(ctx context.Context, argsVars []string, argVarsFiles []io.Reader, environ []string) (map[string]interface{}, error) {
 	var cast = func(vS string) interface{} {
 		var v interface{}
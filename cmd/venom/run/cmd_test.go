@@ -105,3 +105,47 @@ func Test_initFromEnv(t *testing.T) {
 		}
 	}
 }
+
+func Test_initFromReaderConfigFileEnvironments(t *testing.T) {
+	config := `
+variables:
+  - url=http://localhost
+  - user=admin
+variables_files:
+  - vars.yml
+format: json
+environments:
+  staging:
+    variables:
+      - url=https://staging.example.com
+    variables_files:
+      - staging.yml
+    secrets:
+      - password
+    output_dir: results/staging
+`
+	reset := func() {
+		variables, varFiles, secrets, format, outputDir, env = nil, nil, nil, "xml", "", ""
+	}
+	t.Cleanup(reset)
+
+	reset()
+	require.NoError(t, initFromReaderConfigFile(strings.NewReader(config)))
+	require.Equal(t, []string{"url=http://localhost", "user=admin"}, variables)
+	require.Equal(t, "json", format)
+	require.Equal(t, "", outputDir)
+
+	reset()
+	env = "staging"
+	require.NoError(t, initFromReaderConfigFile(strings.NewReader(config)))
+	require.Equal(t, []string{"url=https://staging.example.com", "user=admin"}, variables)
+	require.Equal(t, []string{"vars.yml", "staging.yml"}, varFiles)
+	require.Equal(t, []string{"password"}, secrets)
+	require.Equal(t, "json", format)
+	require.Equal(t, "results/staging", outputDir)
+
+	reset()
+	env = "prod"
+	err := initFromReaderConfigFile(strings.NewReader(config))
+	require.EqualError(t, err, `environment "prod" not found in the configuration file, available environments: staging`)
+}
@@ -0,0 +1,58 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/venom"
+	"github.com/ovh/venom/executors"
+)
+
+var (
+	libDir string
+	output string
+)
+
+func init() {
+	Cmd.Flags().StringVar(&libDir, "lib-dir", "", "Lib Directory: can contain user executors. example:/etc/venom/lib:$HOME/venom.d/lib")
+	Cmd.Flags().StringVarP(&output, "output", "o", "", "Write the schema in this file instead of the standard output")
+}
+
+// Cmd schema
+var Cmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Generate a JSON Schema of the testsuite files",
+	Example: `  Print the JSON Schema: venom schema
+  Write the JSON Schema with the user executors of lib/ in a file: venom schema --lib-dir lib -o venom.schema.json`,
+	Long: `schema generates a JSON Schema of the testsuite files, with the attributes of each executor and user executor.
+It can be used by yaml-language-server to validate and autocomplete the testsuites in your editor.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		v := venom.New()
+		for name, executorFunc := range executors.Registry {
+			v.RegisterExecutorBuiltin(name, executorFunc())
+		}
+		v.LibDir = libDir
+		if libDir == "" {
+			v.LibDir = os.Getenv("VENOM_LIB_DIR")
+		}
+
+		btes, err := json.MarshalIndent(v.JSONSchema("."), "", "  ")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			venom.OSExit(2)
+		}
+
+		if output == "" {
+			fmt.Fprintln(os.Stdout, string(btes))
+			return nil
+		}
+		if err := os.WriteFile(output, append(btes, '\n'), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "unable to write schema: %v\n", err)
+			venom.OSExit(2)
+		}
+		return nil
+	},
+}
@@ -0,0 +1,119 @@
+package serve
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ovh/venom"
+	venomrun "github.com/ovh/venom/cmd/venom/run"
+	"github.com/ovh/venom/executors"
+)
+
+var (
+	listen        string
+	token         string
+	root          string
+	libDir        string
+	maxRuns       int
+	maxHistory    int
+	maxBundleSize int64
+	variables     []string
+	varFiles      []string
+	stopOnFailure bool
+)
+
+func init() {
+	Cmd.Flags().StringVar(&listen, "listen", ":8080", "Address of the HTTP server")
+	Cmd.Flags().StringVar(&token, "token", "", "Token of the requests, given as a bearer token. Can be set with VENOM_SERVE_TOKEN")
+	Cmd.Flags().StringVar(&root, "root", ".", "Directory of the testsuites run by path")
+	Cmd.Flags().StringVar(&libDir, "lib-dir", "", "Lib Directory: can contain user executors. example:/etc/venom/lib:$HOME/venom.d/lib")
+	Cmd.Flags().IntVar(&maxRuns, "max-runs", 1, "Number of runs executed at the same time, the other runs are queued")
+	Cmd.Flags().IntVar(&maxHistory, "max-history", 100, "Number of finished runs kept with their results")
+	Cmd.Flags().Int64Var(&maxBundleSize, "max-bundle-size", DefaultMaxBundleSize, "Maximum size, in bytes, of an uploaded bundle")
+	Cmd.Flags().StringArrayVar(&variables, "var", nil, "Variables of all the runs: --var foo=bar. The variables of a run request override them")
+	Cmd.Flags().StringSliceVar(&varFiles, "var-from-file", nil, "Files of variables of all the runs: yaml, must contains a dictionary")
+	Cmd.Flags().BoolVar(&stopOnFailure, "stop-on-failure", false, "Stop running a Test Suite on first Test Case failure")
+}
+
+// Cmd serve
+var Cmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run testsuites requested over HTTP",
+	Long: `Start an HTTP server which runs testsuites on request, streams their progress and keeps their results.
+All the requests must be authenticated with the token, as a bearer token.
+
+  POST   /runs                 start a run: {"paths": ["tests/"], "variables": {"foo": "bar"}}, paths relative to --root.
+                               Or a multipart form with a "bundle" file, a tar.gz archive of the testsuites, and an
+                               optional "request" field, whose paths are relative to the bundle.
+  GET    /runs                 list the runs
+  GET    /runs/{id}            describe a run
+  GET    /runs/{id}/events     stream the events of a run, as newline delimited JSON
+  GET    /runs/{id}/results    get the results of a finished run: ?format=json, xml, tap, yaml, html, ctrf or markdown
+  DELETE /runs/{id}            cancel a run`,
+	Example: `  VENOM_SERVE_TOKEN=secret venom serve --root tests --max-runs 4
+  curl -H "Authorization: Bearer secret" -d '{"paths": ["api/"]}' http://localhost:8080/runs
+  curl -H "Authorization: Bearer secret" -F bundle=@tests.tar.gz http://localhost:8080/runs`,
+	Args: cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		if token == "" {
+			token = os.Getenv("VENOM_SERVE_TOKEN")
+		}
+		if token == "" {
+			fmt.Fprintf(os.Stderr, "a token is required: --token or VENOM_SERVE_TOKEN\n")
+			venom.OSExit(2)
+		}
+
+		ctx := context.Background()
+		vars, err := venomrun.LoadVariables(ctx, variables, varFiles)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			venom.OSExit(2)
+		}
+
+		s := &Server{
+			Token:         token,
+			Root:          root,
+			MaxRuns:       maxRuns,
+			MaxHistory:    maxHistory,
+			MaxBundleSize: maxBundleSize,
+			Options: venom.Options{
+				Variables:     vars,
+				LibDir:        libDir,
+				StopOnFailure: stopOnFailure,
+			},
+			Executors: func() map[string]venom.Executor {
+				e := map[string]venom.Executor{}
+				for name, executorFunc := range executors.Registry {
+					e[name] = executorFunc()
+				}
+				return e
+			},
+		}
+		srv := &http.Server{
+			Addr:              listen,
+			Handler:           s,
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+
+		ctx, stop := signal.NotifyContext(ctx, os.Interrupt)
+		defer stop()
+		go func() {
+			<-ctx.Done()
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			srv.Shutdown(shutdownCtx) // nolint
+		}()
+
+		fmt.Fprintf(os.Stdout, "listening on %s\n", listen)
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			fmt.Fprintf(os.Stderr, "%v\n", err)
+			venom.OSExit(2)
+		}
+	},
+}
@@ -0,0 +1,578 @@
+package serve
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/ovh/venom"
+)
+
+// Statuses of a run, in addition to the statuses of its results
+const (
+	RunStatusQueued   = "QUEUED"
+	RunStatusRunning  = "RUNNING"
+	RunStatusCanceled = "CANCELED"
+	RunStatusError    = "ERROR"
+)
+
+// DefaultMaxBundleSize is the default maximum size of an uploaded bundle
+const DefaultMaxBundleSize = 32 << 20
+
+// RunRequest is the body of a request to start a run
+type RunRequest struct {
+	// Paths are the testsuites to run, relative to the root directory of the server or of the bundle
+	Paths     []string               `json:"paths"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+// RunSummary describes a run, without its results
+type RunSummary struct {
+	ID               string    `json:"id"`
+	Status           string    `json:"status"`
+	Error            string    `json:"error,omitempty"`
+	Paths            []string  `json:"paths"`
+	Created          time.Time `json:"created"`
+	Start            time.Time `json:"start"`
+	End              time.Time `json:"end"`
+	NbTestsuitesFail int       `json:"nbTestsuitesFail"`
+	NbTestsuitesPass int       `json:"nbTestsuitesPass"`
+	NbTestsuitesSkip int       `json:"nbTestsuitesSkip"`
+}
+
+type run struct {
+	mutex   sync.Mutex
+	summary RunSummary
+	dir     string
+	request RunRequest
+	tests   *venom.Tests
+	events  []venom.Event
+	// changed is closed, then replaced, each time the run changes
+	changed chan struct{}
+	// ctx is canceled once the run is done, or by a DELETE of the run
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+func (r *run) done() bool {
+	switch r.summary.Status {
+	case RunStatusQueued, RunStatusRunning:
+		return false
+	}
+	return true
+}
+
+func (r *run) addEvent(e venom.Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.appendEvent(e)
+}
+
+// appendEvent adds an event, r.mutex must be locked
+func (r *run) appendEvent(e venom.Event) {
+	r.events = append(r.events, e)
+	close(r.changed)
+	r.changed = make(chan struct{})
+}
+
+// Server runs testsuites requested over HTTP, and keeps their results
+type Server struct {
+	// Token authenticates the requests, given as a bearer token
+	Token string
+	// Root is the directory of the testsuites run by path
+	Root string
+	// MaxRuns is the number of runs executed at the same time, the other runs are queued
+	MaxRuns int
+	// MaxHistory is the number of finished runs kept with their results
+	MaxHistory int
+	// MaxBundleSize is the maximum size, in bytes, of an uploaded bundle. Defaults to DefaultMaxBundleSize.
+	MaxBundleSize int64
+	// Options are the options of each run: the variables of a request override their variables
+	Options venom.Options
+	// Executors instantiates the builtin executors of a run
+	Executors func() map[string]venom.Executor
+
+	mutex   sync.Mutex
+	runs    map[string]*run
+	order   []string
+	slots   chan struct{}
+	initOne sync.Once
+}
+
+func (s *Server) init() {
+	s.initOne.Do(func() {
+		s.runs = map[string]*run{}
+		maxRuns := s.MaxRuns
+		if maxRuns <= 0 {
+			maxRuns = 1
+		}
+		s.slots = make(chan struct{}, maxRuns)
+	})
+}
+
+// ServeHTTP routes the requests of the API:
+//
+//	POST   /runs                   start a run, from a JSON RunRequest or from a multipart bundle
+//	GET    /runs                   list the runs
+//	GET    /runs/{id}              describe a run
+//	GET    /runs/{id}/events       stream the events of a run, as newline delimited JSON
+//	GET    /runs/{id}/results      get the results of a finished run: ?format=json, xml, tap, yaml, html, ctrf or markdown
+//	DELETE /runs/{id}              cancel a run
+func (s *Server) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.init()
+	if !s.authenticated(req) {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, http.StatusUnauthorized, errors.New("invalid or missing token"))
+		return
+	}
+
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	if parts[0] != "runs" || len(parts) > 3 {
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+		return
+	}
+	if len(parts) == 1 {
+		switch req.Method {
+		case http.MethodPost:
+			s.handleCreateRun(w, req)
+		case http.MethodGet:
+			s.handleListRuns(w)
+		default:
+			writeError(w, http.StatusMethodNotAllowed, errors.New("method not allowed"))
+		}
+		return
+	}
+
+	r := s.getRun(parts[1])
+	if r == nil {
+		writeError(w, http.StatusNotFound, fmt.Errorf("run %s not found", parts[1]))
+		return
+	}
+	switch {
+	case len(parts) == 2 && req.Method == http.MethodGet:
+		r.mutex.Lock()
+		summary := r.summary
+		r.mutex.Unlock()
+		writeJSON(w, http.StatusOK, summary)
+	case len(parts) == 2 && req.Method == http.MethodDelete:
+		r.cancel()
+		r.mutex.Lock()
+		summary := r.summary
+		r.mutex.Unlock()
+		writeJSON(w, http.StatusAccepted, summary)
+	case len(parts) == 3 && parts[2] == "events" && req.Method == http.MethodGet:
+		s.handleEvents(w, req, r)
+	case len(parts) == 3 && parts[2] == "results" && req.Method == http.MethodGet:
+		s.handleResults(w, req, r)
+	default:
+		writeError(w, http.StatusNotFound, errors.New("not found"))
+	}
+}
+
+func (s *Server) authenticated(req *http.Request) bool {
+	token := strings.TrimPrefix(req.Header.Get("Authorization"), "Bearer ")
+	return s.Token != "" && subtle.ConstantTimeCompare([]byte(token), []byte(s.Token)) == 1
+}
+
+func (s *Server) handleCreateRun(w http.ResponseWriter, req *http.Request) {
+	r, err := s.newRun(w, req)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	s.mutex.Lock()
+	s.runs[r.summary.ID] = r
+	s.order = append(s.order, r.summary.ID)
+	s.mutex.Unlock()
+
+	summary := r.summary
+	go s.execute(r)
+
+	writeJSON(w, http.StatusAccepted, summary)
+}
+
+// newRun reads a run request: a JSON RunRequest, or a multipart form with a "bundle" file, a tar.gz archive of the
+// testsuites, and an optional "request" field, a JSON RunRequest whose paths are relative to the bundle
+func (s *Server) newRun(w http.ResponseWriter, req *http.Request) (*run, error) {
+	id, err := newRunID()
+	if err != nil {
+		return nil, err
+	}
+	r := &run{
+		summary: RunSummary{ID: id, Status: RunStatusQueued, Created: time.Now()},
+		changed: make(chan struct{}),
+	}
+
+	root := s.Root
+	if root == "" {
+		root = "."
+	}
+	maxBundleSize := s.MaxBundleSize
+	if maxBundleSize <= 0 {
+		maxBundleSize = DefaultMaxBundleSize
+	}
+	if strings.HasPrefix(req.Header.Get("Content-Type"), "multipart/form-data") {
+		req.Body = http.MaxBytesReader(w, req.Body, maxBundleSize)
+		if err := req.ParseMultipartForm(maxBundleSize); err != nil {
+			return nil, errors.Wrapf(err, "unable to read the bundle")
+		}
+		defer req.MultipartForm.RemoveAll() // nolint
+		if v := req.FormValue("request"); v != "" {
+			if err := json.Unmarshal([]byte(v), &r.request); err != nil {
+				return nil, errors.Wrapf(err, "invalid request")
+			}
+		}
+		bundle, _, err := req.FormFile("bundle")
+		if err != nil {
+			return nil, errors.Wrapf(err, "bundle is missing")
+		}
+		defer bundle.Close()
+		r.dir, err = os.MkdirTemp("", "venom-serve-")
+		if err != nil {
+			return nil, err
+		}
+		if err := extractBundle(bundle, r.dir, maxExtractedRatio*maxBundleSize); err != nil {
+			os.RemoveAll(r.dir) // nolint
+			return nil, err
+		}
+		root = r.dir
+	} else if err := json.NewDecoder(req.Body).Decode(&r.request); err != nil {
+		return nil, errors.Wrapf(err, "invalid request")
+	}
+
+	if len(r.request.Paths) == 0 {
+		r.request.Paths = []string{"."}
+	}
+	for _, p := range r.request.Paths {
+		path, err := pathInRoot(root, p)
+		if err != nil {
+			if r.dir != "" {
+				os.RemoveAll(r.dir) // nolint
+			}
+			return nil, err
+		}
+		r.summary.Paths = append(r.summary.Paths, path)
+	}
+	// the run may be canceled as soon as it is listed
+	r.ctx, r.cancel = context.WithCancel(context.Background())
+	return r, nil
+}
+
+// execute runs the testsuites of a run once a slot is available
+func (s *Server) execute(r *run) {
+	ctx := r.ctx
+	defer r.cancel()
+	if r.dir != "" {
+		defer os.RemoveAll(r.dir) // nolint
+	}
+
+	select {
+	case s.slots <- struct{}{}:
+		defer func() { <-s.slots }()
+	case <-ctx.Done():
+		s.finish(r, nil, RunStatusCanceled, nil)
+		return
+	}
+
+	r.mutex.Lock()
+	r.summary.Status = RunStatusRunning
+	r.summary.Start = time.Now()
+	r.mutex.Unlock()
+	r.addEvent(venom.Event{Type: venom.EventRunStart, Time: time.Now()})
+
+	opts := s.Options
+	opts.Variables = map[string]interface{}{}
+	for k, v := range s.Options.Variables {
+		opts.Variables[k] = v
+	}
+	for k, v := range r.request.Variables {
+		opts.Variables[k] = v
+	}
+	if s.Executors != nil {
+		opts.Executors = s.Executors()
+	}
+	opts.OutputDir = ""
+	opts.Output = nil
+	if opts.LogOutput == nil {
+		opts.LogOutput = io.Discard
+	}
+	// the run_start and run_end events are the ones of the server, sent even when venom fails to run
+	opts.Reporters = append([]venom.Reporter{venom.NewEventReporter(func(e venom.Event) {
+		if e.Type != venom.EventRunStart && e.Type != venom.EventRunEnd {
+			r.addEvent(e)
+		}
+	})}, s.Options.Reporters...)
+	opts.TestCaseWrapper = func(ctx context.Context, ts *venom.TestSuite, tc *venom.TestCase, run func(ctx context.Context)) {
+		// the testcases which are not run once the run is canceled are skipped
+		if ctx.Err() == nil {
+			run(ctx)
+		}
+	}
+
+	v, err := venom.NewWithOptions(opts)
+	if err != nil {
+		s.finish(r, nil, RunStatusError, err)
+		return
+	}
+	defer v.Close() // nolint
+
+	tests, err := v.Run(ctx, r.summary.Paths)
+	if err != nil {
+		s.finish(r, nil, RunStatusError, err)
+		return
+	}
+	for i := range tests.TestSuites {
+		tests.TestSuites[i] = v.CleanUpSecrets(tests.TestSuites[i])
+	}
+	status := string(tests.Status)
+	if ctx.Err() != nil {
+		status = RunStatusCanceled
+	}
+	s.finish(r, tests, status, nil)
+}
+
+func (s *Server) finish(r *run, tests *venom.Tests, status string, err error) {
+	r.mutex.Lock()
+	r.tests = tests
+	r.summary.Status = status
+	r.summary.End = time.Now()
+	if err != nil {
+		r.summary.Error = err.Error()
+	}
+	if tests != nil {
+		r.summary.NbTestsuitesFail = tests.NbTestsuitesFail
+		r.summary.NbTestsuitesPass = tests.NbTestsuitesPass
+		r.summary.NbTestsuitesSkip = tests.NbTestsuitesSkip
+	}
+	// the last event is added with the final status, so that the streams of events end with it
+	e := venom.Event{Type: venom.EventRunEnd, Time: time.Now(), Status: venom.Status(status)}
+	if err != nil {
+		e.Error = err.Error()
+	}
+	r.appendEvent(e)
+	r.mutex.Unlock()
+
+	s.prune()
+}
+
+// prune forgets the oldest finished runs beyond MaxHistory
+func (s *Server) prune() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if s.MaxHistory <= 0 {
+		return
+	}
+	finished := 0
+	for i := len(s.order) - 1; i >= 0; i-- {
+		r := s.runs[s.order[i]]
+		r.mutex.Lock()
+		done := r.done()
+		r.mutex.Unlock()
+		if !done {
+			continue
+		}
+		finished++
+		if finished > s.MaxHistory {
+			delete(s.runs, s.order[i])
+			s.order = append(s.order[:i], s.order[i+1:]...)
+		}
+	}
+}
+
+func (s *Server) getRun(id string) *run {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.runs[id]
+}
+
+func (s *Server) handleListRuns(w http.ResponseWriter) {
+	s.mutex.Lock()
+	summaries := make([]RunSummary, 0, len(s.order))
+	for _, id := range s.order {
+		r := s.runs[id]
+		r.mutex.Lock()
+		summaries = append(summaries, r.summary)
+		r.mutex.Unlock()
+	}
+	s.mutex.Unlock()
+	sort.SliceStable(summaries, func(i, j int) bool { return summaries[i].Created.After(summaries[j].Created) })
+	writeJSON(w, http.StatusOK, summaries)
+}
+
+// handleEvents writes the events of a run already sent, then the new ones until the run is finished
+func (s *Server) handleEvents(w http.ResponseWriter, req *http.Request, r *run) {
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	enc := json.NewEncoder(w)
+
+	var sent int
+	for {
+		r.mutex.Lock()
+		events := r.events[sent:]
+		changed := r.changed
+		done := r.done()
+		r.mutex.Unlock()
+
+		for _, e := range events {
+			if err := enc.Encode(e); err != nil {
+				return
+			}
+		}
+		sent += len(events)
+		if flusher != nil {
+			flusher.Flush()
+		}
+		if done {
+			return
+		}
+
+		select {
+		case <-changed:
+		case <-req.Context().Done():
+			return
+		}
+	}
+}
+
+func (s *Server) handleResults(w http.ResponseWriter, req *http.Request, r *run) {
+	r.mutex.Lock()
+	tests := r.tests
+	done := r.done()
+	r.mutex.Unlock()
+	if !done {
+		writeError(w, http.StatusConflict, errors.New("the run is not finished"))
+		return
+	}
+	if tests == nil {
+		writeError(w, http.StatusNotFound, errors.New("the run has no results"))
+		return
+	}
+
+	format := req.URL.Query().Get("format")
+	if format == "" {
+		format = "json"
+	}
+	data, err := venom.FormatTests(tests, format)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	switch format {
+	case "json", "ctrf":
+		w.Header().Set("Content-Type", "application/json")
+	case "xml":
+		w.Header().Set("Content-Type", "application/xml")
+	case "html":
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	case "markdown", "md":
+		w.Header().Set("Content-Type", "text/markdown; charset=utf-8")
+	default:
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data) // nolint
+}
+
+// maxExtractedRatio limits the size of the extracted files of a bundle, relatively to the size of the bundle
+const maxExtractedRatio = 10
+
+// extractBundle extracts a tar.gz archive in dir, up to maxSize bytes
+func extractBundle(r io.Reader, dir string, maxSize int64) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Wrapf(err, "bundle must be a tar.gz archive")
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "unable to read the bundle")
+		}
+		target, err := pathInRoot(dir, hdr.Name)
+		if err != nil {
+			return err
+		}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+			if err != nil {
+				return err
+			}
+			n, err := io.Copy(f, io.LimitReader(tr, maxSize+1))
+			if err != nil {
+				f.Close()
+				return err
+			}
+			maxSize -= n
+			if maxSize < 0 {
+				f.Close()
+				return errors.New("the extracted bundle is too large")
+			}
+			if err := f.Close(); err != nil {
+				return err
+			}
+		default:
+			// links and special files are ignored
+		}
+	}
+}
+
+// pathInRoot joins a relative path to root, and rejects the paths outside root
+func pathInRoot(root, p string) (string, error) {
+	if filepath.IsAbs(p) {
+		return "", fmt.Errorf("invalid path %q: must be relative", p)
+	}
+	joined := filepath.Join(root, p)
+	rel, err := filepath.Rel(root, joined)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("invalid path %q: must be inside the root directory", p)
+	}
+	return joined, nil
+}
+
+func newRunID() (string, error) {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func writeJSON(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v) // nolint
+}
+
+func writeError(w http.ResponseWriter, code int, err error) {
+	writeJSON(w, code, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,199 @@
+package serve
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/venom"
+)
+
+const testSuite = `name: greet
+testcases:
+- name: hello
+  steps:
+  - type: echo
+    value: "hello {{.who}}"
+    assertions:
+    - out ShouldEqual "hello world"
+`
+
+type echoExecutor struct{}
+
+func (echoExecutor) Run(ctx context.Context, step venom.TestStep) (interface{}, error) {
+	out, _ := step.StringValue("value")
+	return map[string]interface{}{"out": out}, nil
+}
+
+func newTestServer(t *testing.T, root string) *httptest.Server {
+	s := &Server{
+		Token:   "secret",
+		Root:    root,
+		MaxRuns: 1,
+		Options: venom.Options{Variables: map[string]interface{}{"who": "nobody"}},
+		Executors: func() map[string]venom.Executor {
+			return map[string]venom.Executor{"echo": echoExecutor{}}
+		},
+	}
+	srv := httptest.NewServer(s)
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func do(t *testing.T, method, url, contentType string, body []byte) *http.Response {
+	req, err := http.NewRequest(method, url, bytes.NewReader(body))
+	require.NoError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// waitEvents reads the events of a run until it is finished
+func waitEvents(t *testing.T, url, id string) []venom.Event {
+	resp := do(t, http.MethodGet, url+"/runs/"+id+"/events", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var events []venom.Event
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var e venom.Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, e)
+	}
+	return events
+}
+
+func TestServerRun(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "greet.yml"), []byte(testSuite), 0644))
+	srv := newTestServer(t, root)
+
+	resp, err := http.Post(srv.URL+"/runs", "application/json", strings.NewReader(`{}`))
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = do(t, http.MethodPost, srv.URL+"/runs", "application/json", []byte(`{"paths": ["../greet.yml"]}`))
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	resp = do(t, http.MethodPost, srv.URL+"/runs", "application/json", []byte(`{"paths": ["greet.yml"], "variables": {"who": "world"}}`))
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	var summary RunSummary
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&summary))
+
+	events := waitEvents(t, srv.URL, summary.ID)
+	var types []string
+	for _, e := range events {
+		types = append(types, e.Type)
+	}
+	assert.Equal(t, []string{
+		venom.EventRunStart,
+		venom.EventTestSuiteStart,
+		venom.EventTestCaseStart,
+		venom.EventStepStart,
+		venom.EventStepEnd,
+		venom.EventTestCaseEnd,
+		venom.EventTestSuiteEnd,
+		venom.EventRunEnd,
+	}, types)
+	assert.Equal(t, "hello", events[5].TestCase)
+	assert.Equal(t, venom.StatusPass, events[5].Status)
+	assert.Equal(t, venom.StatusPass, events[7].Status)
+
+	resp = do(t, http.MethodGet, srv.URL+"/runs/"+summary.ID+"/results", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	var tests venom.Tests
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&tests))
+	assert.Equal(t, venom.StatusPass, tests.Status)
+	require.Len(t, tests.TestSuites, 1)
+
+	resp = do(t, http.MethodGet, srv.URL+"/runs/"+summary.ID+"/results?format=xml", "", nil)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/xml", resp.Header.Get("Content-Type"))
+}
+
+func TestServerBundle(t *testing.T) {
+	srv := newTestServer(t, t.TempDir())
+
+	var archive bytes.Buffer
+	gz := gzip.NewWriter(&archive)
+	tw := tar.NewWriter(gz)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "tests/greet.yml", Mode: 0644, Size: int64(len(testSuite)), Typeflag: tar.TypeReg}))
+	_, err := tw.Write([]byte(testSuite))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("bundle", "tests.tar.gz")
+	require.NoError(t, err)
+	_, err = fw.Write(archive.Bytes())
+	require.NoError(t, err)
+	require.NoError(t, mw.WriteField("request", `{"paths": ["tests"]}`))
+	require.NoError(t, mw.Close())
+
+	resp := do(t, http.MethodPost, srv.URL+"/runs", mw.FormDataContentType(), body.Bytes())
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	var summary RunSummary
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&summary))
+
+	events := waitEvents(t, srv.URL, summary.ID)
+	require.NotEmpty(t, events)
+	last := events[len(events)-1]
+	assert.Equal(t, venom.EventRunEnd, last.Type)
+	// the server variables are used without variables in the request
+	assert.Equal(t, venom.StatusFail, last.Status)
+
+	resp = do(t, http.MethodGet, srv.URL+"/runs/"+summary.ID, "", nil)
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&summary))
+	assert.Equal(t, string(venom.StatusFail), summary.Status)
+	assert.Equal(t, 1, summary.NbTestsuitesFail)
+}
+
+func TestPathInRoot(t *testing.T) {
+	p, err := pathInRoot("root", "tests/a.yml")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join("root", "tests", "a.yml"), p)
+
+	_, err = pathInRoot("root", "../a.yml")
+	assert.Error(t, err)
+	_, err = pathInRoot("root", "/etc/passwd")
+	assert.Error(t, err)
+}
+
+func TestServerCancel(t *testing.T) {
+	root := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(root, "greet.yml"), []byte(testSuite), 0644))
+	srv := newTestServer(t, root)
+
+	resp := do(t, http.MethodPost, srv.URL+"/runs", "application/json", []byte(`{"paths": ["greet.yml"]}`))
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	var summary RunSummary
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&summary))
+
+	// a run can be canceled as soon as it is created
+	resp = do(t, http.MethodDelete, srv.URL+"/runs/"+summary.ID, "", nil)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	events := waitEvents(t, srv.URL, summary.ID)
+	require.NotEmpty(t, events)
+	assert.Equal(t, venom.EventRunEnd, events[len(events)-1].Type)
+}
@@ -0,0 +1,159 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/rockbears/yaml"
+)
+
+// Debugger is called before and after each step of the testcases when it is set on Venom.
+// Steps run by user executors are not debugged.
+type Debugger interface {
+	// BeforeStep is called before a step is run. It returns the variables to change:
+	// the step is then interpolated again, and BeforeStep is called again.
+	BeforeStep(ctx context.Context, s *DebugStep) H
+	// AfterStep is called once a step is run, with its result. If it returns true, the step is run again.
+	AfterStep(ctx context.Context, s *DebugStep) bool
+}
+
+// DebugStep is the state of a step given to a Debugger
+type DebugStep struct {
+	TestCase *TestCase
+	// TestCaseName is the name of the testcase, as written in the testsuite
+	TestCaseName string
+	StepNumber   int
+	RangedIndex  int
+	Name         string
+	// Content is the interpolated step, as YAML
+	Content string
+	// Vars are the variables available in the step
+	Vars H
+	// Results are the results of the previous steps of the testcase
+	Results []TestStepResult
+	// Result is the result of the step, once it is run
+	Result *TestStepResult
+	// Err is the error raised by the last interpolation of the step, if any
+	Err error
+}
+
+// Breakpoint pauses the run before a step of a testcase. Step is -1 to pause before the first step.
+type Breakpoint struct {
+	TestCase string
+	Step     int
+	StepName string
+}
+
+// ParseBreakpoint parses a breakpoint: testcase[:step], where step is the number or the name of a step
+func ParseBreakpoint(s string) (Breakpoint, error) {
+	b := Breakpoint{Step: -1}
+	idx := strings.LastIndex(s, ":")
+	if idx < 0 {
+		b.TestCase = s
+	} else {
+		b.TestCase = s[:idx]
+		if n, err := strconv.Atoi(s[idx+1:]); err == nil {
+			b.Step = n
+		} else {
+			b.StepName = s[idx+1:]
+		}
+	}
+	if b.TestCase == "" {
+		return b, fmt.Errorf("invalid breakpoint %q: testcase is missing", s)
+	}
+	return b, nil
+}
+
+// Match returns true if the run must be paused before a step
+func (b Breakpoint) Match(s *DebugStep) bool {
+	tc := s.TestCase
+	if b.TestCase != tc.Name && b.TestCase != s.TestCaseName && b.TestCase != tc.ID {
+		return false
+	}
+	switch {
+	case b.StepName != "":
+		return b.StepName == s.Name || strings.HasPrefix(s.Name, b.StepName+" (range=")
+	case b.Step >= 0:
+		return b.Step == s.StepNumber
+	}
+	return s.StepNumber == 0
+}
+
+// debugBeforeStep gives a step to the debugger before it is run. When variables are changed by the debugger,
+// the step is interpolated again: the new context, executor and step are returned.
+func (v *Venom) debugBeforeStep(ctx context.Context, tc *TestCase, tsResult *TestStepResult, e ExecutorRunner, step TestStep, stepVars H, rawStep json.RawMessage, content string) (context.Context, ExecutorRunner, TestStep, *DebugStep) {
+	s := &DebugStep{
+		TestCase:     tc,
+		TestCaseName: tc.originalName,
+		StepNumber:   tsResult.Number,
+		RangedIndex:  tsResult.RangedIndex,
+		Name:         tsResult.Name,
+		Vars:         AllVarsFromCtx(ctx),
+		Results:      tc.TestStepResults[:len(tc.TestStepResults)-1],
+		Content:      stepYAML(content),
+	}
+	for {
+		edits := v.Debugger.BeforeStep(ctx, s)
+		if len(edits) == 0 {
+			return ctx, e, step, s
+		}
+		stepVars.AddAll(edits)
+
+		vars, newContent, err := interpolateStep(stepVars, rawStep)
+		if err != nil {
+			s.Err = err
+			continue
+		}
+		var newStep TestStep
+		if err := yaml.Unmarshal([]byte(newContent), &newStep); err != nil {
+			s.Err = fmt.Errorf("unable to parse step: %v", err)
+			continue
+		}
+		newCtx, newE, err := v.GetExecutorRunner(ctx, newStep, stepVars)
+		if err != nil {
+			s.Err = err
+			continue
+		}
+		ctx, e, step, content = newCtx, newE, newStep, newContent
+		tsResult.InputVars = vars
+		tsResult.Interpolated, _ = yaml.JSONToYAML([]byte(content)) //nolint
+		s.Err = nil
+		s.Vars = AllVarsFromCtx(ctx)
+		s.Content = stepYAML(content)
+	}
+}
+
+// debugAfterStep gives the result of a step to the debugger, it returns true to run the step again
+func (v *Venom) debugAfterStep(ctx context.Context, s *DebugStep, tsResult *TestStepResult) bool {
+	if s == nil {
+		return false
+	}
+	s.Result = tsResult
+	if !v.Debugger.AfterStep(ctx, s) {
+		return false
+	}
+
+	// keep the interpolated step, but forget the result of the previous run
+	*tsResult = TestStepResult{
+		Name:         tsResult.Name,
+		Raw:          tsResult.Raw,
+		Interpolated: tsResult.Interpolated,
+		Number:       tsResult.Number,
+		RangedIndex:  tsResult.RangedIndex,
+		RangedEnable: tsResult.RangedEnable,
+		InputVars:    tsResult.InputVars,
+	}
+	s.Result = nil
+	return true
+}
+
+func stepYAML(content string) string {
+	btes, err := yaml.JSONToYAML([]byte(content))
+	if err != nil {
+		return content
+	}
+	return string(btes)
+}
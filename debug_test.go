@@ -0,0 +1,92 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type debugTestExecutor struct {
+	runs int
+}
+
+func (e *debugTestExecutor) Run(ctx context.Context, step TestStep) (interface{}, error) {
+	e.runs++
+	out, _ := step.StringValue("value")
+	return map[string]interface{}{"out": out}, nil
+}
+
+type debugTestDebugger struct {
+	before, after int
+}
+
+func (d *debugTestDebugger) BeforeStep(ctx context.Context, s *DebugStep) H {
+	d.before++
+	if d.before == 1 {
+		return H{"who": "venom"}
+	}
+	return nil
+}
+
+func (d *debugTestDebugger) AfterStep(ctx context.Context, s *DebugStep) bool {
+	d.after++
+	return d.after == 1
+}
+
+func TestParseBreakpoint(t *testing.T) {
+	b, err := ParseBreakpoint("my testcase")
+	require.NoError(t, err)
+	assert.Equal(t, Breakpoint{TestCase: "my testcase", Step: -1}, b)
+
+	b, err = ParseBreakpoint("my testcase:2")
+	require.NoError(t, err)
+	assert.Equal(t, Breakpoint{TestCase: "my testcase", Step: 2}, b)
+
+	b, err = ParseBreakpoint("my testcase:create user")
+	require.NoError(t, err)
+	assert.Equal(t, Breakpoint{TestCase: "my testcase", Step: -1, StepName: "create user"}, b)
+
+	_, err = ParseBreakpoint(":2")
+	assert.Error(t, err)
+}
+
+func TestBreakpointMatch(t *testing.T) {
+	tc := &TestCase{TestCaseInput: TestCaseInput{Name: "my-testcase", ID: "tc-1"}}
+	s := &DebugStep{TestCase: tc, TestCaseName: "my testcase", StepNumber: 1, Name: "create user (range=0)"}
+
+	assert.True(t, Breakpoint{TestCase: "my testcase", Step: 1}.Match(s))
+	assert.True(t, Breakpoint{TestCase: "tc-1", Step: -1, StepName: "create user"}.Match(s))
+	assert.False(t, Breakpoint{TestCase: "my-testcase", Step: -1}.Match(s))
+	assert.False(t, Breakpoint{TestCase: "other", Step: 1}.Match(s))
+}
+
+func TestDebugger(t *testing.T) {
+	InitTestLogger(t)
+	e := &debugTestExecutor{}
+	d := &debugTestDebugger{}
+	v := New()
+	v.RegisterExecutorBuiltin("echo", e)
+	v.Debugger = d
+
+	step, err := json.Marshal(map[string]interface{}{
+		"type":       "echo",
+		"value":      "hello {{.who}}",
+		"assertions": []string{"out ShouldEqual \"hello venom\""},
+	})
+	require.NoError(t, err)
+	tc := &TestCase{
+		TestCaseInput: TestCaseInput{Name: "tc", Vars: H{"who": "world"}, RawTestSteps: []json.RawMessage{step}},
+		computedVars:  H{},
+	}
+
+	v.runTestSteps(context.Background(), tc, nil)
+
+	require.Len(t, tc.TestStepResults, 1)
+	assert.Equal(t, StatusPass, tc.TestStepResults[0].Status)
+	assert.Equal(t, 2, d.before)
+	assert.Equal(t, 2, d.after)
+	assert.Equal(t, 2, e.runs)
+}
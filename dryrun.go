@@ -0,0 +1,157 @@
+package venom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rockbears/yaml"
+)
+
+// DryRunRuntimeValue is the value, in a dry run, of the variables which depend on the results of the previous steps
+const DryRunRuntimeValue = "<runtime>"
+
+// DryRunStep is a step rendered by a dry run: interpolated, but not run
+type DryRunStep struct {
+	Name        string   `json:"name" yaml:"name"`
+	Number      int      `json:"number" yaml:"number"`
+	RangedIndex *int     `json:"rangedIndex,omitempty" yaml:"rangedIndex,omitempty"`
+	Executor    string   `json:"executor" yaml:"executor"`
+	Step        TestStep `json:"step" yaml:"step"`
+	// Steps are the steps of a user executor
+	Steps []DryRunStep `json:"steps,omitempty" yaml:"steps,omitempty"`
+}
+
+// dryRunStep renders a step instead of running it. The steps of user executors are rendered too.
+func (v *Venom) dryRunStep(ctx context.Context, e ExecutorRunner, tc *TestCase, tsResult *TestStepResult, step TestStep, content string) {
+	s := DryRunStep{
+		Name:     tsResult.Name,
+		Number:   tsResult.Number,
+		Executor: e.Name(),
+	}
+	if tsResult.RangedEnable {
+		rangedIndex := tsResult.RangedIndex
+		s.RangedIndex = &rangedIndex
+	}
+	if err := yaml.Unmarshal([]byte(HideSensitive(ctx, content)), &s.Step); err != nil {
+		tsResult.appendError(errors.Wrapf(err, "unable to parse step"))
+	}
+	if tsResult.RangedEnable {
+		// the range is expanded: each iteration is rendered as a step
+		delete(s.Step, "range")
+	}
+
+	if e.Type() == "user" {
+		utc, _, err := v.userExecutorTestCase(ctx, e, tc, step)
+		if err != nil {
+			tsResult.appendError(err)
+		} else {
+			v.runTestSteps(ctx, utc, tsResult)
+			s.Steps = utc.DryRunSteps
+		}
+	}
+
+	tc.DryRunSteps = append(tc.DryRunSteps, s)
+	if len(tsResult.Errors) > 0 {
+		tsResult.Status = StatusFail
+	} else {
+		tsResult.Status = StatusPass
+	}
+}
+
+// dryRunVariableAssignments returns the variables assigned by a step. As they are computed from its results, their
+// values are only known at runtime.
+func dryRunVariableAssignments(rawStep json.RawMessage) (H, error) {
+	var stepAssignment AssignStep
+	if err := yaml.Unmarshal(rawStep, &stepAssignment); err != nil {
+		return nil, errors.Wrapf(err, "unable to parse assignments")
+	}
+	result := H{}
+	for varname := range stepAssignment.Assignments {
+		result.Add(varname, DryRunRuntimeValue)
+	}
+	return result, nil
+}
+
+// DryRunTestSuite is a testsuite rendered by a dry run
+type DryRunTestSuite struct {
+	Name      string           `json:"name" yaml:"name"`
+	Filename  string           `json:"filename" yaml:"filename"`
+	TestCases []DryRunTestCase `json:"testcases" yaml:"testcases"`
+}
+
+// DryRunTestCase is a testcase rendered by a dry run
+type DryRunTestCase struct {
+	Name    string       `json:"name" yaml:"name"`
+	Skipped bool         `json:"skipped,omitempty" yaml:"skipped,omitempty"`
+	Steps   []DryRunStep `json:"steps" yaml:"steps"`
+}
+
+// DryRunResult returns the steps rendered by a dry run, for each testsuite
+func (v *Venom) DryRunResult() []DryRunTestSuite {
+	var res []DryRunTestSuite
+	for _, ts := range v.Tests.TestSuites {
+		dts := DryRunTestSuite{Name: ts.Name, Filename: ts.Filepath}
+		for _, tc := range ts.TestCases {
+			name := tc.originalName
+			if name == "" {
+				name = tc.Name
+			}
+			dts.TestCases = append(dts.TestCases, DryRunTestCase{
+				Name:    name,
+				Skipped: tc.Status == StatusSkip,
+				Steps:   tc.DryRunSteps,
+			})
+		}
+		res = append(res, dts)
+	}
+	return res
+}
+
+// WriteDryRun writes the steps rendered by a dry run as YAML documents, one for each testsuite
+func WriteDryRun(w io.Writer, testsuites []DryRunTestSuite) error {
+	for i, ts := range testsuites {
+		btes, err := yaml.Marshal(ts)
+		if err != nil {
+			return errors.Wrapf(err, "unable to marshal testsuite %q", ts.Name)
+		}
+		if i > 0 {
+			if _, err := io.WriteString(w, "---\n"); err != nil {
+				return err
+			}
+		}
+		if _, err := w.Write(btes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OutputDryRun writes the steps rendered by a dry run in the output directory, in a file for each testsuite
+func (v *Venom) OutputDryRun() error {
+	if v.OutputDir == "" {
+		return nil
+	}
+	testsuites := v.DryRunResult()
+	for _, ts := range testsuites {
+		var buf bytes.Buffer
+		if err := WriteDryRun(&buf, []DryRunTestSuite{ts}); err != nil {
+			return err
+		}
+		fname := strings.TrimSuffix(ts.Filename, filepath.Ext(ts.Filename))
+		fname = strings.ReplaceAll(fname, "/", "_")
+		filename := path.Join(v.OutputDir, "dry_run_"+fname+".yml")
+		if err := os.WriteFile(filename, buf.Bytes(), 0600); err != nil {
+			return fmt.Errorf("Error while creating file %s: %v", filename, err)
+		}
+		v.PrintFunc("Writing file %s\n", filename)
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+package venom
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDryRun(t *testing.T) {
+	InitTestLogger(t)
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "greet.yml"), []byte(`executor: greet
+input:
+  who: world
+steps:
+- type: echo
+  value: "hello {{.input.who}}"
+  vars:
+    greeting:
+      from: out
+output:
+  greeting: "{{.greeting}}"
+`), 0644))
+
+	e := &debugTestExecutor{}
+	v := New()
+	v.LibDir = dir
+	v.DryRun = true
+	v.RegisterExecutorBuiltin("echo", e)
+
+	var steps []json.RawMessage
+	for _, s := range []map[string]interface{}{
+		{"type": "echo", "value": "{{.who}}", "vars": map[string]interface{}{"out": map[string]interface{}{"from": "result.out"}}},
+		{"type": "echo", "value": "{{.value}} got {{.out}}", "range": []string{"a", "b"}},
+		{"type": "greet", "who": "venom"},
+	} {
+		step, err := json.Marshal(s)
+		require.NoError(t, err)
+		steps = append(steps, step)
+	}
+	tc := &TestCase{
+		TestCaseInput: TestCaseInput{Name: "tc", Vars: H{"who": "world", "venom.testsuite.workdir": dir}, RawTestSteps: steps},
+		TestSuiteVars: H{"venom.testsuite.workdir": dir},
+		computedVars:  H{},
+	}
+
+	v.runTestSteps(context.Background(), tc, nil)
+
+	assert.Equal(t, 0, e.runs)
+	for _, r := range tc.TestStepResults {
+		assert.Equal(t, StatusPass, r.Status, r.Errors)
+	}
+	require.Len(t, tc.DryRunSteps, 4)
+	assert.Equal(t, "world", tc.DryRunSteps[0].Step["value"])
+	assert.Equal(t, "a got <runtime>", tc.DryRunSteps[1].Step["value"])
+	assert.Equal(t, "b got <runtime>", tc.DryRunSteps[2].Step["value"])
+	assert.NotContains(t, tc.DryRunSteps[2].Step, "range")
+	require.NotNil(t, tc.DryRunSteps[2].RangedIndex)
+	assert.Equal(t, 1, *tc.DryRunSteps[2].RangedIndex)
+
+	greet := tc.DryRunSteps[3]
+	assert.Equal(t, "greet", greet.Executor)
+	require.Len(t, greet.Steps, 1)
+	assert.Equal(t, "hello venom", greet.Steps[0].Step["value"])
+	assert.Equal(t, DryRunRuntimeValue, tc.computedVars["out"])
+
+	v.Tests.TestSuites = []TestSuite{{Name: "ts", Filepath: "ts.yml", TestCases: []TestCase{*tc}}}
+	var buf bytes.Buffer
+	require.NoError(t, WriteDryRun(&buf, v.DryRunResult()))
+	assert.Contains(t, buf.String(), "value: a got <runtime>")
+	assert.Contains(t, buf.String(), "value: hello venom")
+}
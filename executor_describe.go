@@ -0,0 +1,179 @@
+package venom
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// ExecutorWithDescription is an optional interface for executors which document themselves.
+// Inputs and outputs which are not described are computed from the executor struct and from ZeroValueResult.
+type ExecutorWithDescription interface {
+	Describe() ExecutorDescription
+}
+
+// ExecutorDescription documents an executor: the attributes of a step, the results and the default assertions
+type ExecutorDescription struct {
+	Name              string          `json:"name" yaml:"name"`
+	Kind              string          `json:"kind" yaml:"kind"`
+	Description       string          `json:"description,omitempty" yaml:"description,omitempty"`
+	Filename          string          `json:"filename,omitempty" yaml:"filename,omitempty"`
+	Inputs            []ExecutorField `json:"inputs" yaml:"inputs"`
+	Outputs           []ExecutorField `json:"outputs" yaml:"outputs"`
+	DefaultAssertions []Assertion     `json:"default_assertions,omitempty" yaml:"default_assertions,omitempty"`
+}
+
+// DescribeExecutors describes the builtin and plugin executors registered, and the user executors
+// available for a testsuite located in workdir
+func (v *Venom) DescribeExecutors(workdir string) []ExecutorDescription {
+	var descriptions []ExecutorDescription
+	for _, kind := range []string{ExecutorKindBuiltin, ExecutorKindPlugin} {
+		registered := v.executorsBuiltin
+		if kind == ExecutorKindPlugin {
+			registered = v.executorsPlugin
+		}
+		names := make([]string, 0, len(registered))
+		for name := range registered {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			descriptions = append(descriptions, describeExecutor(name, kind, registered[name]))
+		}
+	}
+
+	for _, ux := range v.UserExecutorsInfo(workdir) {
+		descriptions = append(descriptions, v.describeUserExecutor(ux))
+	}
+	return descriptions
+}
+
+// DescribeExecutor describes an executor by its name
+func (v *Venom) DescribeExecutor(name, workdir string) (*ExecutorDescription, error) {
+	for _, d := range v.DescribeExecutors(workdir) {
+		if d.Name == name {
+			return &d, nil
+		}
+	}
+	return nil, fmt.Errorf("executor %q not found", name)
+}
+
+func describeExecutor(name, kind string, e Executor) ExecutorDescription {
+	d := ExecutorDescription{
+		Name:   name,
+		Kind:   kind,
+		Inputs: ExecutorFields(e),
+	}
+	if x, ok := e.(executorWithZeroValueResult); ok {
+		d.Outputs = resultFields(x.ZeroValueResult())
+	}
+	if x, ok := e.(executorWithDefaultAssertions); ok {
+		if a := x.GetDefaultAssertions(); a != nil {
+			d.DefaultAssertions = a.Assertions
+		}
+	}
+	if x, ok := e.(ExecutorWithDescription); ok {
+		described := x.Describe()
+		if described.Description != "" {
+			d.Description = described.Description
+		}
+		d.Inputs = mergeFields(d.Inputs, described.Inputs)
+		d.Outputs = mergeFields(d.Outputs, described.Outputs)
+		if len(described.DefaultAssertions) > 0 {
+			d.DefaultAssertions = described.DefaultAssertions
+		}
+	}
+	if d.Inputs == nil {
+		d.Inputs = []ExecutorField{}
+	}
+	if d.Outputs == nil {
+		d.Outputs = []ExecutorField{}
+	}
+	return d
+}
+
+func (v *Venom) describeUserExecutor(ux UserExecutorInfo) ExecutorDescription {
+	d := ExecutorDescription{
+		Name:     ux.Name,
+		Kind:     ExecutorKindUser,
+		Filename: ux.Filename,
+		Inputs:   []ExecutorField{},
+		Outputs:  []ExecutorField{},
+	}
+
+	var input map[string]interface{}
+	if root, err := v.parseYAMLNode(ux.Filename); err == nil && root != nil {
+		if node := mappingValue(root, "input"); node != nil {
+			node.Decode(&input) //nolint
+		}
+	}
+	for _, i := range ux.Inputs {
+		d.Inputs = append(d.Inputs, ExecutorField{Name: i, Default: input[i]})
+	}
+	for _, o := range ux.Outputs {
+		d.Outputs = append(d.Outputs, ExecutorField{Name: o})
+	}
+	return d
+}
+
+// resultFields computes the results of an executor from the json tags of its zero value result
+func resultFields(result interface{}) []ExecutorField {
+	if result == nil {
+		return nil
+	}
+	t := reflect.TypeOf(result)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var fields []ExecutorField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		name := tagName(sf.Tag.Get("json"))
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = strings.ToLower(sf.Name)
+		}
+		fields = append(fields, ExecutorField{
+			Name:   name,
+			Type:   jsonType(sf.Type),
+			GoType: sf.Type,
+		})
+	}
+	return fields
+}
+
+// mergeFields completes computed fields with the described ones, described fields unknown from computed are appended
+func mergeFields(computed, described []ExecutorField) []ExecutorField {
+	for _, f := range described {
+		var found bool
+		for i := range computed {
+			if !computed[i].Match(f.Name) {
+				continue
+			}
+			found = true
+			if f.Description != "" {
+				computed[i].Description = f.Description
+			}
+			if f.Type != "" {
+				computed[i].Type = f.Type
+			}
+			if f.Default != nil {
+				computed[i].Default = f.Default
+			}
+		}
+		if !found {
+			computed = append(computed, f)
+		}
+	}
+	return computed
+}
@@ -0,0 +1,82 @@
+package venom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type describedTestExecutor struct {
+	Command string `json:"command" yaml:"command"`
+	DialURL string `json:"dialURL" yaml:"dialURL" mapstructure:"dialURL"`
+}
+
+func (describedTestExecutor) Run(context.Context, TestStep) (interface{}, error) {
+	return nil, nil
+}
+
+type describedTestResult struct {
+	Code      int    `json:"code"`
+	Systemout string `json:"systemout"`
+}
+
+func (describedTestExecutor) ZeroValueResult() interface{} {
+	return describedTestResult{}
+}
+
+func (describedTestExecutor) GetDefaultAssertions() *StepAssertions {
+	return &StepAssertions{Assertions: []Assertion{"result.code ShouldEqual 0"}}
+}
+
+func (describedTestExecutor) Describe() ExecutorDescription {
+	return ExecutorDescription{
+		Description: "A test executor",
+		Inputs:      []ExecutorField{{Name: "command", Description: "Command to run"}},
+		Outputs:     []ExecutorField{{Name: "extra", Type: "string"}},
+	}
+}
+
+func TestDescribeExecutor(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "lib"), 0755))
+	userExecutor := `executor: myexecutor
+input:
+  foo: bar
+steps:
+- script: echo {{.input.foo}}
+output:
+  result: "{{.result.systemout}}"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lib", "myexecutor.yml"), []byte(userExecutor), 0644))
+
+	v := New()
+	v.RegisterExecutorBuiltin("test", describedTestExecutor{})
+
+	d, err := v.DescribeExecutor("test", dir)
+	require.NoError(t, err)
+	assert.Equal(t, ExecutorKindBuiltin, d.Kind)
+	assert.Equal(t, "A test executor", d.Description)
+	require.Len(t, d.Inputs, 2)
+	assert.Equal(t, "command", d.Inputs[0].Name)
+	assert.Equal(t, "Command to run", d.Inputs[0].Description)
+	var outputs []string
+	for _, o := range d.Outputs {
+		outputs = append(outputs, o.Name)
+	}
+	assert.Equal(t, []string{"code", "systemout", "extra"}, outputs)
+	assert.Equal(t, "integer", d.Outputs[0].Type)
+	assert.Equal(t, []Assertion{"result.code ShouldEqual 0"}, d.DefaultAssertions)
+
+	d, err = v.DescribeExecutor("myexecutor", dir)
+	require.NoError(t, err)
+	assert.Equal(t, ExecutorKindUser, d.Kind)
+	assert.Equal(t, []ExecutorField{{Name: "foo", Default: "bar"}}, d.Inputs)
+	assert.Equal(t, []ExecutorField{{Name: "result"}}, d.Outputs)
+
+	_, err = v.DescribeExecutor("unknown", dir)
+	assert.Error(t, err)
+}
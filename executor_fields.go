@@ -0,0 +1,291 @@
+package venom
+
+import (
+	"context"
+	"os"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/ovh/cds/sdk/interpolate"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+)
+
+// StepKeywords are the attributes handled by venom itself on every step, whatever the executor.
+var StepKeywords = []string{
+	"type",
+	"name",
+	"retry",
+	"retry_if",
+	"delay",
+	"timeout",
+	"info",
+	"range",
+	"skip",
+	"vars",
+	"assertions",
+	"aggregate_assertions",
+}
+
+// IsStepKeyword returns true if key is handled by venom on every step
+func IsStepKeyword(key string) bool {
+	for _, k := range StepKeywords {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// ExecutorField describes an attribute accepted by an executor in a step
+type ExecutorField struct {
+	Name        string       `json:"name" yaml:"name"`
+	Aliases     []string     `json:"aliases,omitempty" yaml:"aliases,omitempty"`
+	Type        string       `json:"type,omitempty" yaml:"type,omitempty"`
+	Description string       `json:"description,omitempty" yaml:"description,omitempty"`
+	Default     interface{}  `json:"default,omitempty" yaml:"default,omitempty"`
+	GoType      reflect.Type `json:"-" yaml:"-"`
+}
+
+// Match returns true if key is decoded into the field
+func (f ExecutorField) Match(key string) bool {
+	if strings.EqualFold(f.Name, key) {
+		return true
+	}
+	for _, a := range f.Aliases {
+		if strings.EqualFold(a, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// ExecutorFields computes the attributes accepted by an executor from its struct and its
+// mapstructure, json and yaml tags. It returns nil if the executor is not a struct.
+func ExecutorFields(e Executor) []ExecutorField {
+	if e == nil {
+		return nil
+	}
+	t := reflect.TypeOf(e)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return nil
+	}
+	fields := structFields(t)
+	sort.Slice(fields, func(i, j int) bool { return fields[i].Name < fields[j].Name })
+	return fields
+}
+
+func structFields(t reflect.Type) []ExecutorField {
+	var fields []ExecutorField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+
+		mapstructureTag := tagName(sf.Tag.Get("mapstructure"))
+		// like mapstructure, embedded structs are only flattened with the squash option
+		if strings.Contains(sf.Tag.Get("mapstructure"), ",squash") {
+			ft := sf.Type
+			for ft.Kind() == reflect.Ptr {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				fields = append(fields, structFields(ft)...)
+				continue
+			}
+		}
+		if mapstructureTag == "-" {
+			continue
+		}
+
+		field := ExecutorField{
+			Name:   strings.ToLower(sf.Name),
+			Type:   jsonType(sf.Type),
+			GoType: sf.Type,
+		}
+		for _, n := range []string{tagName(sf.Tag.Get("json")), tagName(sf.Tag.Get("yaml"))} {
+			if n != "" && n != "-" && !field.Match(n) {
+				field.Aliases = append(field.Aliases, n)
+			}
+		}
+		// mapstructure tag is the name really used to decode the step, json and yaml tags are
+		// kept as aliases because some executors read the step attributes by these names
+		if mapstructureTag != "" {
+			field.Name = mapstructureTag
+		} else if len(field.Aliases) > 0 {
+			field.Aliases = append(field.Aliases, field.Name)
+			field.Name = field.Aliases[0]
+		}
+		aliases := field.Aliases[:0]
+		for _, a := range field.Aliases {
+			if !strings.EqualFold(a, field.Name) && !isInList(a, aliases) {
+				aliases = append(aliases, a)
+			}
+		}
+		field.Aliases = aliases
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+func tagName(tag string) string {
+	return strings.Split(tag, ",")[0]
+}
+
+// jsonType returns the JSON type matching a Go type
+func jsonType(t reflect.Type) string {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return "integer"
+	case reflect.Float32, reflect.Float64:
+		return "number"
+	case reflect.String:
+		return "string"
+	case reflect.Slice, reflect.Array:
+		return "array"
+	case reflect.Map, reflect.Struct:
+		return "object"
+	}
+	return ""
+}
+
+// UserExecutorInfo describes a user executor found in a lib directory
+type UserExecutorInfo struct {
+	Name     string   `json:"name" yaml:"name"`
+	Filename string   `json:"filename" yaml:"filename"`
+	Inputs   []string `json:"inputs,omitempty" yaml:"inputs,omitempty"`
+	Outputs  []string `json:"outputs,omitempty" yaml:"outputs,omitempty"`
+}
+
+// UserExecutorsInfo reads the user executors available for a testsuite located in workdir, without registering them.
+// Files which can't be parsed are ignored.
+func (v *Venom) UserExecutorsInfo(workdir string) []UserExecutorInfo {
+	executorsPath, _ := v.getUserExecutorFilesPath(map[string]string{"venom.testsuite.workdir": workdir})
+
+	var infos []UserExecutorInfo
+	for _, f := range executorsPath {
+		node, err := v.parseYAMLNode(f)
+		if err != nil || node == nil {
+			continue
+		}
+		name := scalarValue(mappingValue(node, "executor"))
+		if name == "" {
+			continue
+		}
+		infos = append(infos, UserExecutorInfo{
+			Name:     name,
+			Filename: f,
+			Inputs:   mappingKeys(mappingValue(node, "input")),
+			Outputs:  mappingKeys(mappingValue(node, "output")),
+		})
+	}
+	return infos
+}
+
+// parseYAMLNode parses a file as a YAML node. If the raw content is not a valid YAML document,
+// because of unquoted templates, it is interpolated with the variables known at this step.
+func (v *Venom) parseYAMLNode(filename string) (*yaml.Node, error) {
+	btes, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to read file %q", filename)
+	}
+
+	var doc yaml.Node
+	errRaw := yaml.Unmarshal(btes, &doc)
+	if errRaw != nil {
+		vars, _ := DumpStringPreserveCase(v.variables)
+		if vars == nil {
+			vars = map[string]string{}
+		}
+		if fromPartial, err := getVarFromPartialYML(context.Background(), btes); err == nil {
+			partial, _ := DumpStringPreserveCase(fromPartial)
+			for k, v := range partial {
+				if _, ok := vars[k]; !ok {
+					vars[k] = v
+				}
+			}
+		}
+		content, err := interpolate.Do(string(btes), vars)
+		if err != nil {
+			return nil, errRaw
+		}
+		if err := yaml.Unmarshal([]byte(content), &doc); err != nil {
+			return nil, errRaw
+		}
+	}
+	if len(doc.Content) == 0 {
+		return nil, nil
+	}
+	return doc.Content[0], nil
+}
+
+// testCaseLines returns the line of each testcase of a testsuite, nil if it is not a valid YAML document
+func testCaseLines(content []byte) []int {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil || len(doc.Content) == 0 {
+		return nil
+	}
+	testcases := mappingValue(doc.Content[0], "testcases")
+	if testcases == nil || testcases.Kind != yaml.SequenceNode {
+		return nil
+	}
+	lines := make([]int, len(testcases.Content))
+	for i, tc := range testcases.Content {
+		lines[i] = tc.Line
+	}
+	return lines
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
+
+func mappingKeys(node *yaml.Node) []string {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	var keys []string
+	for i := 0; i < len(node.Content); i += 2 {
+		keys = append(keys, node.Content[i].Value)
+	}
+	return keys
+}
+
+func scalarValue(node *yaml.Node) string {
+	if node == nil || node.Kind != yaml.ScalarNode {
+		return ""
+	}
+	return node.Value
+}
+
+func scalarValues(node *yaml.Node) []string {
+	if node == nil || node.Kind != yaml.SequenceNode {
+		return nil
+	}
+	var values []string
+	for _, n := range node.Content {
+		if n.Kind == yaml.ScalarNode {
+			values = append(values, n.Value)
+		}
+	}
+	return values
+}
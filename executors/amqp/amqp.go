@@ -108,7 +108,7 @@ func (e Executor) publishMessages(ctx context.Context, session *amqp.Session) (i
 	}
 
 	for _, m := range e.Messages {
-		if err := sender.Send(ctx, amqp.NewMessage([]byte(m))); err != nil {
+		if err := sender.Send(ctx, newMessage(ctx, m)); err != nil {
 			return nil, fmt.Errorf("publishing messages: %w", err)
 		}
 	}
@@ -116,6 +116,16 @@ func (e Executor) publishMessages(ctx context.Context, session *amqp.Session) (i
 	return nil, nil
 }
 
+// newMessage returns a message with the traceparent of the run in its application properties
+func newMessage(ctx context.Context, body string) *amqp.Message {
+	msg := amqp.NewMessage([]byte(body))
+	// the messages of the step have no application properties
+	venom.InjectTraceParent(ctx, func(string) bool { return false }, func(k, v string) {
+		msg.ApplicationProperties = map[string]any{k: v}
+	})
+	return msg
+}
+
 func (e Executor) consumeMessages(ctx context.Context, session *amqp.Session) (interface{}, error) {
 	if e.SourceAddr == "" {
 		return nil, errors.New("consuming messages: sourceAddr is manatory when clientType is consumer")
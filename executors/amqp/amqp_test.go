@@ -0,0 +1,19 @@
+package amqp
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ovh/venom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewMessage_TraceParent(t *testing.T) {
+	ctx := venom.ContextWithTraceParent(context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	msg := newMessage(ctx, "hello")
+	assert.Equal(t, [][]byte{[]byte("hello")}, msg.Data)
+	assert.Equal(t, map[string]any{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}, msg.ApplicationProperties)
+
+	assert.Nil(t, newMessage(context.Background(), "hello").ApplicationProperties)
+}
@@ -52,6 +52,25 @@ func (Executor) GetDefaultAssertions() *venom.StepAssertions {
 	return &venom.StepAssertions{Assertions: []venom.Assertion{"result.code ShouldEqual 0"}}
 }
 
+// Describe documents the exec executor
+func (Executor) Describe() venom.ExecutorDescription {
+	return venom.ExecutorDescription{
+		Description: "Execute a script with the shell of the operating system",
+		Inputs: []venom.ExecutorField{
+			{Name: "script", Description: "Script to execute, it can be multiline"},
+		},
+		Outputs: []venom.ExecutorField{
+			{Name: "systemout", Description: "Standard output of the script"},
+			{Name: "systemoutjson", Description: "Standard output parsed as JSON, if possible"},
+			{Name: "systemerr", Description: "Error output of the script"},
+			{Name: "systemerrjson", Description: "Error output parsed as JSON, if possible"},
+			{Name: "err", Description: "Error message if the script could not be run"},
+			{Name: "code", Description: "Exit code of the script"},
+			{Name: "timeseconds", Description: "Duration of the script in seconds"},
+		},
+	}
+}
+
 // Run execute TestStep of type exec
 func (Executor) Run(ctx context.Context, step venom.TestStep) (interface{}, error) {
 	var e Executor
@@ -11,6 +11,7 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/fullstorydev/grpcurl"
@@ -105,6 +106,26 @@ func (Executor) GetDefaultAssertions() *venom.StepAssertions {
 	return &venom.StepAssertions{Assertions: []venom.Assertion{"result.code ShouldEqual 0"}}
 }
 
+// requestHeaders returns the metadata of the request, with the traceparent of the run unless the testsuite sets its own
+// traceparent
+func (e Executor) requestHeaders(ctx context.Context) []string {
+	headers := make([]string, len(e.Headers))
+	for k, v := range e.Headers {
+		headers = append(headers, fmt.Sprintf("%s: %s", k, v))
+	}
+	venom.InjectTraceParent(ctx, func(k string) bool {
+		for h := range e.Headers {
+			if strings.EqualFold(h, k) {
+				return true
+			}
+		}
+		return false
+	}, func(k, v string) {
+		headers = append(headers, k+": "+v)
+	})
+	return headers
+}
+
 // Run execute TestStep of type exec
 func (Executor) Run(ctx context.Context, step venom.TestStep) (interface{}, error) {
 	// decode test
@@ -114,10 +135,7 @@ func (Executor) Run(ctx context.Context, step venom.TestStep) (interface{}, erro
 	}
 
 	// prepare headers
-	headers := make([]string, len(e.Headers))
-	for k, v := range e.Headers {
-		headers = append(headers, fmt.Sprintf("%s: %s", k, v))
-	}
+	headers := e.requestHeaders(ctx)
 
 	// prepare data
 	data, err := json.Marshal(e.Data)
@@ -0,0 +1,26 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ovh/venom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExecutor_requestHeaders_TraceParent(t *testing.T) {
+	ctx := venom.ContextWithTraceParent(context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	e := Executor{Headers: map[string]string{"authorization": "Bearer token"}}
+	headers := e.requestHeaders(ctx)
+	assert.Contains(t, headers, "authorization: Bearer token")
+	assert.Contains(t, headers, "traceparent: 00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	// the traceparent of the step is kept
+	e = Executor{Headers: map[string]string{"TraceParent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}}
+	headers = e.requestHeaders(ctx)
+	assert.Contains(t, headers, "TraceParent: 00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	assert.NotContains(t, headers, "traceparent: 00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	assert.NotContains(t, Executor{}.requestHeaders(context.Background()), "traceparent: ")
+}
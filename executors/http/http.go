@@ -115,6 +115,8 @@ func (Executor) Run(ctx context.Context, step venom.TestStep) (interface{}, erro
 			req.Host = v
 		}
 	}
+	// join the trace of the run, unless the testsuite sets its own traceparent
+	venom.InjectTraceParent(ctx, func(k string) bool { return req.Header.Get(k) != "" }, req.Header.Set)
 
 	var opts []func(*http.Transport) error
 	opts = append(opts, WithProxyFromEnv())
@@ -4,6 +4,8 @@ import (
 	"context"
 	"fmt"
 	"io"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"os/exec"
 	"testing"
@@ -125,3 +127,23 @@ func TestInterpolation_without_match_Of_String(t *testing.T) {
 	require.Errorf(t, err, "unable to interpolate file due to unresolved variables {{.name}}")
 
 }
+
+func TestExecutor_Run_TraceParent(t *testing.T) {
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	defer server.Close()
+	ctx := venom.ContextWithTraceParent(context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	_, err := Executor{}.Run(ctx, venom.TestStep{"method": "GET", "url": server.URL})
+	require.NoError(t, err)
+	require.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01", traceparent)
+
+	// the traceparent of the step is kept
+	_, err = Executor{}.Run(ctx, venom.TestStep{"method": "GET", "url": server.URL, "headers": map[string]string{
+		"TraceParent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+	}})
+	require.NoError(t, err)
+	require.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", traceparent)
+}
@@ -146,7 +146,7 @@ func (Executor) Run(ctx context.Context, step venom.TestStep) (interface{}, erro
 	switch e.ClientType {
 	case "producer":
 		workdir := venom.StringVarFromCtx(ctx, "venom.testsuite.workdir")
-		err := e.produceMessages(workdir)
+		err := e.produceMessages(ctx, workdir)
 		if err != nil {
 			result.Err = err.Error()
 		}
@@ -166,7 +166,7 @@ func (Executor) Run(ctx context.Context, step venom.TestStep) (interface{}, erro
 	return result, nil
 }
 
-func (e Executor) produceMessages(workdir string) error {
+func (e Executor) produceMessages(ctx context.Context, workdir string) error {
 	if len(e.Messages) == 0 && e.MessagesFile == "" {
 		return fmt.Errorf("Either one of `messages` or `messagesFile` field must be set")
 	}
@@ -212,10 +212,11 @@ func (e Executor) produceMessages(workdir string) error {
 		if err != nil {
 			return err
 		}
+		headers := messageHeaders(ctx, message.Headers)
 		messages = append(messages, &sarama.ProducerMessage{
 			Topic:   message.Topic,
 			Key:     sarama.ByteEncoder([]byte(message.Key)),
-			Headers: convertToRecordHeaders(message.Headers),
+			Headers: headers,
 			Value:   sarama.ByteEncoder(value),
 		})
 	}
@@ -547,6 +548,23 @@ func convertFromMessage2JSON(message *Message, msgJSON *MessageJSON) {
 	}
 }
 
+// messageHeaders returns the headers of a message, with the traceparent of the run unless the message has its own
+// traceparent
+func messageHeaders(ctx context.Context, headers map[string]string) []sarama.RecordHeader {
+	results := convertToRecordHeaders(headers)
+	venom.InjectTraceParent(ctx, func(k string) bool {
+		for h := range headers {
+			if strings.EqualFold(h, k) {
+				return true
+			}
+		}
+		return false
+	}, func(k, v string) {
+		results = append(results, sarama.RecordHeader{Key: []byte(k), Value: []byte(v)})
+	})
+	return results
+}
+
 func convertToRecordHeaders(headers map[string]string) []sarama.RecordHeader {
 	results := make([]sarama.RecordHeader, len(headers))
 	idx := 0
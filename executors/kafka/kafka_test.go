@@ -0,0 +1,28 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Shopify/sarama"
+	"github.com/ovh/venom"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageHeaders_TraceParent(t *testing.T) {
+	ctx := venom.ContextWithTraceParent(context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	headers := messageHeaders(ctx, map[string]string{"id": "1"})
+	assert.ElementsMatch(t, []sarama.RecordHeader{
+		{Key: []byte("id"), Value: []byte("1")},
+		{Key: []byte("traceparent"), Value: []byte("00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")},
+	}, headers)
+
+	// the traceparent of the message is kept, whatever its case
+	headers = messageHeaders(ctx, map[string]string{"TraceParent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"})
+	assert.Equal(t, []sarama.RecordHeader{
+		{Key: []byte("TraceParent"), Value: []byte("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")},
+	}, headers)
+
+	assert.Empty(t, messageHeaders(context.Background(), nil))
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/mitchellh/mapstructure"
@@ -186,6 +187,7 @@ func (e Executor) publishMessages(ctx context.Context, workdir string) error {
 		if !e.Messages[i].Persistent {
 			deliveryMode = amqp.Transient
 		}
+		headers := messageHeaders(ctx, e.Messages[i].Headers)
 		err = ch.Publish(
 			e.Exchange, // exchange
 			routingKey, // routing key
@@ -196,7 +198,7 @@ func (e Executor) publishMessages(ctx context.Context, workdir string) error {
 				ContentType:     e.Messages[i].ContentType,
 				ContentEncoding: e.Messages[i].ContentEncoding,
 				Body:            []byte(e.Messages[i].Value),
-				Headers:         e.Messages[i].Headers,
+				Headers:         headers,
 			})
 
 		if err != nil {
@@ -208,6 +210,27 @@ func (e Executor) publishMessages(ctx context.Context, workdir string) error {
 	return nil
 }
 
+// messageHeaders returns the headers of a message, with the traceparent of the run unless the message has its own
+// traceparent
+func messageHeaders(ctx context.Context, headers amqp.Table) amqp.Table {
+	results := headers
+	venom.InjectTraceParent(ctx, func(k string) bool {
+		for h := range headers {
+			if strings.EqualFold(h, k) {
+				return true
+			}
+		}
+		return false
+	}, func(k, v string) {
+		// the headers of the step are not changed
+		results = amqp.Table{k: v}
+		for h, value := range headers {
+			results[h] = value
+		}
+	})
+	return results
+}
+
 func (e Executor) consumeMessages(ctx context.Context) ([]string, []interface{}, []interface{}, []amqp.Table, error) {
 	uri, err := amqp.ParseURI(e.Addrs)
 	if err != nil {
@@ -0,0 +1,27 @@
+package rabbitmq
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ovh/venom"
+	"github.com/streadway/amqp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageHeaders_TraceParent(t *testing.T) {
+	ctx := venom.ContextWithTraceParent(context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	headers := amqp.Table{"id": "1"}
+	assert.Equal(t, amqp.Table{
+		"id":          "1",
+		"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01",
+	}, messageHeaders(ctx, headers))
+	assert.Equal(t, amqp.Table{"id": "1"}, headers)
+
+	// the traceparent of the message is kept, whatever its case
+	headers = amqp.Table{"TraceParent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	assert.Equal(t, headers, messageHeaders(ctx, headers))
+
+	assert.Nil(t, messageHeaders(context.Background(), nil))
+}
@@ -18,6 +18,7 @@ require (
 	github.com/golang/protobuf v1.5.3
 	github.com/google/go-github v17.0.0+incompatible
 	github.com/gosimple/slug v1.13.1
+	github.com/gosimple/unidecode v1.0.1
 	github.com/inconshreveable/go-update v0.0.0-20160112193335-8152e7eb6ccf
 	github.com/jhump/protoreflect v1.15.1
 	github.com/jmoiron/sqlx v1.3.5
@@ -33,6 +34,7 @@ require (
 	github.com/ovh/cds/sdk/interpolate v0.0.0-20230310144753-13590d1ea079
 	github.com/ovh/go-ovh v1.3.0
 	github.com/pkg/errors v0.9.1
+	github.com/pmezard/go-difflib v1.0.0
 	github.com/rockbears/yaml v0.1.0
 	github.com/rubenv/sql-migrate v1.4.0
 	github.com/sijms/go-ora v1.3.2
@@ -66,7 +68,6 @@ require (
 	github.com/google/go-querystring v1.1.0 // indirect
 	github.com/google/uuid v1.3.0 // indirect
 	github.com/gorilla/websocket v1.5.0 // indirect
-	github.com/gosimple/unidecode v1.0.1 // indirect
 	github.com/hashicorp/errwrap v1.1.0 // indirect
 	github.com/hashicorp/go-multierror v1.1.1 // indirect
 	github.com/hashicorp/go-uuid v1.0.3 // indirect
@@ -85,7 +86,6 @@ require (
 	github.com/montanaflynn/stats v0.7.0 // indirect
 	github.com/mxk/go-imap v0.0.0-20150429134902-531c36c3f12d // indirect
 	github.com/pierrec/lz4/v4 v4.1.17 // indirect
-	github.com/pmezard/go-difflib v1.0.0 // indirect
 	github.com/rcrowley/go-metrics v0.0.0-20201227073835-cf1acfcdf475 // indirect
 	github.com/remyoudompheng/bigfft v0.0.0-20230129092748-24d4a6f8daec // indirect
 	github.com/tj/go-naturaldate v1.3.0
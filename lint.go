@@ -0,0 +1,545 @@
+package venom
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v3"
+
+	"github.com/ovh/venom/assertions"
+)
+
+var (
+	templateRegEx      = regexp.MustCompile(`{{(.*?)}}`)
+	templateVarRegEx   = regexp.MustCompile(`\.([A-Za-z_][A-Za-z0-9_\-]*)((\.[A-Za-z0-9_\-]+)*)`)
+	yamlErrorLineRegEx = regexp.MustCompile(`line (\d+)`)
+
+	testSuiteKeywords = []string{"name", "vars", "secrets", "testcases"}
+	testCaseKeywords  = []string{"name", "id", "tags", "vars", "skip", "steps"}
+	assignKeywords    = []string{"from", "regex", "default"}
+	logicalOperators  = []string{"and", "or", "xor", "not"}
+)
+
+// LintIssue is a problem found by Lint in a testsuite or in a user executor
+type LintIssue struct {
+	Filename string `json:"filename" yaml:"filename"`
+	Line     int    `json:"line" yaml:"line"`
+	Severity string `json:"severity" yaml:"severity"`
+	Message  string `json:"message" yaml:"message"`
+}
+
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", i.Filename, i.Line, i.Severity, i.Message)
+}
+
+type linter struct {
+	v             *Venom
+	filename      string
+	issues        []LintIssue
+	userExecutors map[string]UserExecutorInfo
+	workdir       string
+}
+
+// Lint statically checks testsuites without running them: executor types and attributes,
+// assertions, range, skip and vars blocks and variables usage.
+func (v *Venom) Lint(ctx context.Context, path []string) ([]LintIssue, error) {
+	filesPath, err := getFilesPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []LintIssue
+	lintedExecutors := map[string]bool{}
+	for _, f := range filesPath {
+		l, err := v.newLinter(f)
+		if err != nil {
+			return nil, err
+		}
+		l.lintTestSuite(ctx)
+		issues = append(issues, l.issues...)
+
+		// user executors are linted once, even if they are used by several testsuites
+		for _, ux := range l.userExecutors {
+			if lintedExecutors[ux.Filename] {
+				continue
+			}
+			lintedExecutors[ux.Filename] = true
+			lx := &linter{v: v, filename: ux.Filename, userExecutors: l.userExecutors, workdir: l.workdir}
+			lx.lintUserExecutor(ctx, ux)
+			issues = append(issues, lx.issues...)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Filename != issues[j].Filename {
+			return issues[i].Filename < issues[j].Filename
+		}
+		return issues[i].Line < issues[j].Line
+	})
+	return issues, nil
+}
+
+func (v *Venom) newLinter(filename string) (*linter, error) {
+	workdir, err := filepath.Abs(filepath.Dir(filename))
+	if err != nil {
+		return nil, errors.Wrapf(err, "unable to get testsuite's working directory")
+	}
+	l := &linter{
+		v:             v,
+		filename:      filename,
+		workdir:       workdir,
+		userExecutors: map[string]UserExecutorInfo{},
+	}
+	for _, ux := range v.UserExecutorsInfo(workdir) {
+		l.userExecutors[ux.Name] = ux
+	}
+	return l, nil
+}
+
+func (l *linter) report(node *yaml.Node, severity string, format string, args ...interface{}) {
+	var line int
+	if node != nil {
+		line = node.Line
+	}
+	l.issues = append(l.issues, LintIssue{
+		Filename: l.filename,
+		Line:     line,
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (l *linter) lintTestSuite(ctx context.Context) {
+	root, err := l.v.parseYAMLNode(l.filename)
+	if err != nil {
+		line := 0
+		if m := yamlErrorLineRegEx.FindStringSubmatch(err.Error()); len(m) == 2 {
+			line, _ = strconv.Atoi(m[1])
+		}
+		l.issues = append(l.issues, LintIssue{Filename: l.filename, Line: line, Severity: SeverityError, Message: err.Error()})
+		return
+	}
+	if root == nil {
+		l.report(nil, SeverityError, "empty testsuite")
+		return
+	}
+	if root.Kind != yaml.MappingNode {
+		l.report(root, SeverityError, "a testsuite must be a map")
+		return
+	}
+	l.checkKeys(root, testSuiteKeywords, SeverityWarning, "testsuite")
+
+	vars := mappingValue(root, "vars")
+	if vars != nil && vars.Kind != yaml.MappingNode {
+		l.report(vars, SeverityError, "\"vars\" must be a map")
+	}
+
+	testcases := mappingValue(root, "testcases")
+	if testcases == nil {
+		l.report(root, SeverityWarning, "no testcases defined")
+		return
+	}
+	if testcases.Kind != yaml.SequenceNode {
+		l.report(testcases, SeverityError, "\"testcases\" must be a list")
+		return
+	}
+
+	// assigned holds the variables assigned with "vars" by each testcase
+	assigned := map[string]map[string]*yaml.Node{}
+	for _, tc := range testcases.Content {
+		if tc.Kind != yaml.MappingNode {
+			l.report(tc, SeverityError, "a testcase must be a map")
+			continue
+		}
+		l.checkKeys(tc, testCaseKeywords, SeverityWarning, "testcase")
+		name := scalarValue(mappingValue(tc, "name"))
+		if name == "" {
+			l.report(tc, SeverityWarning, "testcase without name")
+		}
+		if tcVars := mappingValue(tc, "vars"); tcVars != nil && tcVars.Kind != yaml.MappingNode {
+			l.report(tcVars, SeverityError, "\"vars\" must be a map")
+		}
+		l.lintConditions(mappingValue(tc, "skip"), "skip")
+		tcAssigned := map[string]*yaml.Node{}
+		assigned[slugify(name)] = tcAssigned
+
+		steps := mappingValue(tc, "steps")
+		if steps == nil {
+			continue
+		}
+		if steps.Kind != yaml.SequenceNode {
+			l.report(steps, SeverityError, "\"steps\" must be a list")
+			continue
+		}
+		for _, step := range steps.Content {
+			for k, n := range l.lintStep(step) {
+				tcAssigned[k] = n
+			}
+		}
+	}
+
+	l.lintVariables(vars, assigned)
+}
+
+func (l *linter) lintUserExecutor(ctx context.Context, ux UserExecutorInfo) {
+	name := ux.Name
+	root, err := l.v.parseYAMLNode(ux.Filename)
+	if err != nil || root == nil || root.Kind != yaml.MappingNode {
+		l.report(nil, SeverityError, "unable to parse user executor %q", name)
+		return
+	}
+	l.checkKeys(root, []string{"executor", "input", "steps", "output"}, SeverityWarning, "user executor")
+	steps := mappingValue(root, "steps")
+	if steps == nil || steps.Kind != yaml.SequenceNode {
+		l.report(root, SeverityError, "user executor %q must have a list of steps", name)
+		return
+	}
+	for _, step := range steps.Content {
+		l.lintStep(step)
+	}
+}
+
+// lintStep checks a step and returns the variables it assigns
+func (l *linter) lintStep(step *yaml.Node) map[string]*yaml.Node {
+	if step.Kind != yaml.MappingNode {
+		l.report(step, SeverityError, "a step must be a map")
+		return nil
+	}
+
+	typeNode := mappingValue(step, "type")
+	name := scalarValue(typeNode)
+	if name == "" && mappingValue(step, "script") != nil {
+		name = "exec"
+	}
+
+	var allowed func(key string) bool
+	switch {
+	case name == "":
+		allowed = func(string) bool { return false }
+	case strings.Contains(name, "{{"):
+		allowed = nil
+	default:
+		if e, ok := l.v.executorsBuiltin[name]; ok {
+			if fields := ExecutorFields(e); fields != nil {
+				allowed = func(key string) bool {
+					for _, f := range fields {
+						if f.Match(key) {
+							return true
+						}
+					}
+					return false
+				}
+			}
+		} else if ux, ok := l.userExecutors[name]; ok {
+			allowed = func(key string) bool {
+				for _, i := range ux.Inputs {
+					if i == key {
+						return true
+					}
+				}
+				return false
+			}
+		} else if pluginExists(l.workdir, name) {
+			allowed = nil
+		} else {
+			l.report(typeNode, SeverityError, "unknown executor type %q", name)
+		}
+	}
+
+	var assigned map[string]*yaml.Node
+	for i := 0; i < len(step.Content); i += 2 {
+		key, value := step.Content[i], step.Content[i+1]
+		switch key.Value {
+		case "type", "name":
+		case "retry", "delay", "timeout":
+			if !isTemplate(value) {
+				if _, err := strconv.Atoi(value.Value); err != nil || value.Kind != yaml.ScalarNode {
+					l.report(value, SeverityError, "%q must be an integer", key.Value)
+				}
+			}
+		case "info":
+			// an unquoted template is parsed as a flow mapping
+			if value.Kind == yaml.MappingNode && value.Style&yaml.FlowStyle == 0 {
+				l.report(value, SeverityError, "\"info\" must be a string or a list of strings")
+			}
+		case "retry_if":
+			l.lintConditions(value, key.Value)
+		case "skip":
+			l.lintConditions(value, key.Value)
+		case "range":
+			l.lintRange(value)
+		case "assertions":
+			l.lintAssertions(value)
+		case "aggregate_assertions":
+			if mappingValue(step, "range") == nil {
+				l.report(key, SeverityWarning, "\"aggregate_assertions\" is only evaluated on a ranged step")
+			}
+			l.lintAssertions(value)
+		case "vars":
+			assigned = l.lintAssignments(value)
+		default:
+			if allowed != nil && !allowed(key.Value) {
+				if name == "" {
+					l.report(key, SeverityError, "unknown field %q: a step without type can only contain assertions", key.Value)
+				} else {
+					l.report(key, SeverityError, "unknown field %q for executor %q", key.Value, name)
+				}
+			}
+		}
+	}
+	return assigned
+}
+
+// lintConditions checks "skip" and "retry_if" conditions
+func (l *linter) lintConditions(node *yaml.Node, attribute string) {
+	if node == nil {
+		return
+	}
+	switch node.Kind {
+	case yaml.ScalarNode:
+		l.lintAssertionString(node)
+	case yaml.SequenceNode:
+		for _, n := range node.Content {
+			if n.Kind != yaml.ScalarNode {
+				l.report(n, SeverityError, "%q must be a list of assertions", attribute)
+				continue
+			}
+			l.lintAssertionString(n)
+		}
+	default:
+		l.report(node, SeverityError, "%q must be a list of assertions", attribute)
+	}
+}
+
+func (l *linter) lintAssertions(node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		l.report(node, SeverityError, "assertions must be a list")
+		return
+	}
+	for _, n := range node.Content {
+		l.lintAssertion(n)
+	}
+}
+
+func (l *linter) lintAssertion(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		l.lintAssertionString(node)
+	case yaml.MappingNode:
+		var operators int
+		for i := 0; i < len(node.Content); i += 2 {
+			key, value := node.Content[i], node.Content[i+1]
+			if key.Value == "severity" {
+				if value.Value != SeverityError && value.Value != SeverityWarning {
+					l.report(value, SeverityError, "unsupported assertion severity %q", value.Value)
+				}
+				continue
+			}
+			operators++
+			if !isInList(key.Value, logicalOperators) {
+				l.report(key, SeverityError, "unsupported assertion operator %q", key.Value)
+				continue
+			}
+			l.lintAssertions(value)
+		}
+		if operators != 1 {
+			l.report(node, SeverityError, "expected exactly 1 logical operator but %d were provided", operators)
+		}
+	default:
+		l.report(node, SeverityError, "unsupported assertion format")
+	}
+}
+
+func (l *linter) lintAssertionString(node *yaml.Node) {
+	assert := splitAssertion(node.Value)
+	if len(assert) < 2 {
+		l.report(node, SeverityError, "assertion syntax error: %q", node.Value)
+		return
+	}
+	verb := assert[1]
+	if strings.Contains(verb, "{{") {
+		return
+	}
+	for _, prefix := range []string{"Must", "Warn"} {
+		if strings.HasPrefix(verb, prefix) {
+			verb = strings.Replace(verb, prefix, "Should", 1)
+		}
+	}
+	if _, ok := assertions.Get(verb); !ok {
+		l.report(node, SeverityError, "unsupported assertion %q", assert[1])
+	}
+}
+
+func (l *linter) lintRange(node *yaml.Node) {
+	switch node.Kind {
+	case yaml.SequenceNode, yaml.MappingNode:
+		return
+	case yaml.ScalarNode:
+		if isTemplate(node) {
+			return
+		}
+		if _, err := strconv.Atoi(node.Value); err == nil {
+			return
+		}
+		var data interface{}
+		if err := yaml.Unmarshal([]byte(node.Value), &data); err == nil {
+			switch data.(type) {
+			case []interface{}, map[string]interface{}:
+				return
+			}
+		}
+	}
+	l.report(node, SeverityError, "\"range\" must be a list, a map, an integer or a template")
+}
+
+// lintAssignments checks the "vars" block of a step and returns the assigned variables
+func (l *linter) lintAssignments(node *yaml.Node) map[string]*yaml.Node {
+	if node.Kind != yaml.MappingNode {
+		l.report(node, SeverityError, "\"vars\" must be a map")
+		return nil
+	}
+	assigned := map[string]*yaml.Node{}
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		assigned[key.Value] = key
+		if value.Kind != yaml.MappingNode {
+			l.report(value, SeverityError, "variable %q must be a map with a \"from\" attribute", key.Value)
+			continue
+		}
+		l.checkKeys(value, assignKeywords, SeverityError, fmt.Sprintf("variable %q", key.Value))
+		if scalarValue(mappingValue(value, "from")) == "" {
+			l.report(value, SeverityError, "variable %q must have a \"from\" attribute", key.Value)
+		}
+		if regex := mappingValue(value, "regex"); regex != nil && !isTemplate(regex) {
+			if _, err := regexp.Compile(regex.Value); err != nil {
+				l.report(regex, SeverityError, "invalid regex for variable %q: %v", key.Value, err)
+			}
+		}
+	}
+	return assigned
+}
+
+// lintVariables reports unused testsuite and step variables, and references to undefined testcase variables
+func (l *linter) lintVariables(vars *yaml.Node, assigned map[string]map[string]*yaml.Node) {
+	btes, err := os.ReadFile(l.filename)
+	if err != nil {
+		return
+	}
+	lines := strings.Split(string(btes), "\n")
+
+	type reference struct {
+		line int
+		path []string
+	}
+	var references []reference
+	for i, line := range lines {
+		for _, expr := range templateRegEx.FindAllStringSubmatch(line, -1) {
+			for _, m := range templateVarRegEx.FindAllStringSubmatch(expr[1], -1) {
+				path := []string{m[1]}
+				if m[2] != "" {
+					path = append(path, strings.Split(strings.TrimPrefix(m[2], "."), ".")...)
+				}
+				references = append(references, reference{line: i + 1, path: path})
+			}
+		}
+	}
+
+	isReferenced := func(name string) bool {
+		for _, r := range references {
+			if strings.Join(r.path, ".") == name || strings.HasPrefix(strings.Join(r.path, "."), name+".") {
+				return true
+			}
+			// {{.testcase.var}}
+			if len(r.path) > 1 && strings.Join(r.path[1:], ".") == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	if vars != nil && vars.Kind == yaml.MappingNode {
+		for i := 0; i < len(vars.Content); i += 2 {
+			key := vars.Content[i]
+			if !isReferenced(key.Value) && !l.isReferencedByUserExecutors(key.Value) && !l.isExecutorSetting(key.Value) {
+				l.report(key, SeverityWarning, "variable %q is never used", key.Value)
+			}
+		}
+	}
+
+	for _, tcVars := range assigned {
+		for name, node := range tcVars {
+			if !isReferenced(name) {
+				l.report(node, SeverityWarning, "variable %q is assigned but never used", name)
+			}
+		}
+	}
+
+	for _, r := range references {
+		if len(r.path) < 2 {
+			continue
+		}
+		tcVars, ok := assigned[r.path[0]]
+		if !ok {
+			continue
+		}
+		if _, ok := tcVars[r.path[1]]; !ok {
+			l.issues = append(l.issues, LintIssue{
+				Filename: l.filename,
+				Line:     r.line,
+				Severity: SeverityError,
+				Message:  fmt.Sprintf("variable %q is not defined in testcase %q", r.path[1], r.path[0]),
+			})
+		}
+	}
+}
+
+// isExecutorSetting returns true if the variable configures an executor, such as "redis.dialURL" or "web"
+func (l *linter) isExecutorSetting(name string) bool {
+	for executor := range l.v.executorsBuiltin {
+		if name == executor || strings.HasPrefix(name, executor+".") {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *linter) isReferencedByUserExecutors(name string) bool {
+	re := regexp.MustCompile(`{{[^}]*\.` + regexp.QuoteMeta(name) + `\b`)
+	for _, ux := range l.userExecutors {
+		btes, err := os.ReadFile(ux.Filename)
+		if err == nil && re.Match(btes) {
+			return true
+		}
+	}
+	return false
+}
+
+// checkKeys reports the keys of a map which are not in the allowed list
+func (l *linter) checkKeys(node *yaml.Node, allowed []string, severity, context string) {
+	for i := 0; i < len(node.Content); i += 2 {
+		key := node.Content[i]
+		if !isInList(key.Value, allowed) {
+			l.report(key, severity, "unknown field %q in %s", key.Value, context)
+		}
+	}
+}
+
+func isTemplate(node *yaml.Node) bool {
+	return node != nil && node.Kind == yaml.ScalarNode && strings.Contains(node.Value, "{{")
+}
+
+func isInList(s string, list []string) bool {
+	for _, e := range list {
+		if e == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,78 @@
+package venom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lintTestExecutor struct {
+	Command string `json:"command" yaml:"command"`
+	DialURL string `json:"dialURL" yaml:"dialURL" mapstructure:"dialURL"`
+}
+
+func (lintTestExecutor) Run(context.Context, TestStep) (interface{}, error) {
+	return nil, nil
+}
+
+func TestExecutorFields(t *testing.T) {
+	fields := ExecutorFields(lintTestExecutor{})
+	require.Len(t, fields, 2)
+	assert.Equal(t, "command", fields[0].Name)
+	assert.Equal(t, "string", fields[0].Type)
+	assert.Equal(t, "dialURL", fields[1].Name)
+	assert.True(t, fields[1].Match("dialurl"))
+	assert.False(t, fields[1].Match("url"))
+}
+
+func TestLint(t *testing.T) {
+	dir := t.TempDir()
+	content := `name: lint
+vars:
+  used: foo
+  unused: bar
+testcases:
+- name: first
+  steps:
+  - type: test
+    command: echo {{.used}}
+    comand: typo
+    retry: abc
+    assertions:
+    - result.code ShouldEqal 0
+    - result.code MustEqual 0
+    vars:
+      out:
+        from: result.systemout
+- name: second
+  steps:
+  - type: unknown
+  - type: test
+    command: echo {{.first.out}} {{.first.missing}}
+`
+	filename := filepath.Join(dir, "lint.yml")
+	require.NoError(t, os.WriteFile(filename, []byte(content), 0644))
+
+	v := New()
+	v.RegisterExecutorBuiltin("test", lintTestExecutor{})
+	issues, err := v.Lint(context.Background(), []string{filename})
+	require.NoError(t, err)
+
+	var got []string
+	for _, i := range issues {
+		assert.Equal(t, filename, i.Filename)
+		got = append(got, LintIssue{Line: i.Line, Severity: i.Severity, Message: i.Message}.String())
+	}
+	assert.Equal(t, []string{
+		":4: warning: variable \"unused\" is never used",
+		":10: error: unknown field \"comand\" for executor \"test\"",
+		":11: error: \"retry\" must be an integer",
+		":13: error: unsupported assertion \"ShouldEqal\"",
+		":20: error: unknown executor type \"unknown\"",
+		":22: error: variable \"missing\" is not defined in testcase \"first\"",
+	}, got)
+}
@@ -0,0 +1,151 @@
+package venom
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Kinds of executors
+const (
+	ExecutorKindBuiltin = "builtin"
+	ExecutorKindUser    = "user"
+	ExecutorKindPlugin  = "plugin"
+	ExecutorKindUnknown = "unknown"
+)
+
+// Inventory describes the testsuites selected by a path, without running them
+type Inventory struct {
+	TestSuites    []TestSuiteInventory `json:"testsuites" yaml:"testsuites"`
+	UserExecutors []UserExecutorInfo   `json:"user_executors" yaml:"user_executors"`
+}
+
+// TestSuiteInventory describes a testsuite file
+type TestSuiteInventory struct {
+	Filename  string              `json:"filename" yaml:"filename"`
+	Name      string              `json:"name" yaml:"name"`
+	TestCases []TestCaseInventory `json:"testcases" yaml:"testcases"`
+	Error     string              `json:"error,omitempty" yaml:"error,omitempty"`
+}
+
+// TestCaseInventory describes a testcase: its tags, its number of steps and the executors used by its steps
+type TestCaseInventory struct {
+	Name      string          `json:"name" yaml:"name"`
+	ID        string          `json:"id,omitempty" yaml:"id,omitempty"`
+	Tags      []string        `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Steps     int             `json:"steps" yaml:"steps"`
+	Executors []ExecutorUsage `json:"executors" yaml:"executors"`
+}
+
+// ExecutorUsage is an executor used by a testcase
+type ExecutorUsage struct {
+	Name string `json:"name" yaml:"name"`
+	Kind string `json:"kind" yaml:"kind"`
+}
+
+func (e ExecutorUsage) String() string {
+	return e.Name + " (" + e.Kind + ")"
+}
+
+// List reads the testsuites selected by path, and the user executors available for them
+func (v *Venom) List(ctx context.Context, path []string) (*Inventory, error) {
+	filesPath, err := getFilesPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	inventory := &Inventory{
+		TestSuites:    []TestSuiteInventory{},
+		UserExecutors: []UserExecutorInfo{},
+	}
+	userExecutorFiles := map[string]bool{}
+	for _, f := range filesPath {
+		workdir, err := filepath.Abs(filepath.Dir(f))
+		if err != nil {
+			return nil, err
+		}
+		userExecutors := map[string]UserExecutorInfo{}
+		for _, ux := range v.UserExecutorsInfo(workdir) {
+			userExecutors[ux.Name] = ux
+			if !userExecutorFiles[ux.Filename] {
+				userExecutorFiles[ux.Filename] = true
+				inventory.UserExecutors = append(inventory.UserExecutors, ux)
+			}
+		}
+		inventory.TestSuites = append(inventory.TestSuites, v.inventoryTestSuite(f, workdir, userExecutors))
+	}
+
+	sort.Slice(inventory.UserExecutors, func(i, j int) bool {
+		return inventory.UserExecutors[i].Name < inventory.UserExecutors[j].Name
+	})
+	return inventory, nil
+}
+
+func (v *Venom) inventoryTestSuite(filename, workdir string, userExecutors map[string]UserExecutorInfo) TestSuiteInventory {
+	ts := TestSuiteInventory{Filename: filename, TestCases: []TestCaseInventory{}}
+	root, err := v.parseYAMLNode(filename)
+	if err != nil {
+		ts.Error = err.Error()
+		return ts
+	}
+	ts.Name = scalarValue(mappingValue(root, "name"))
+
+	testcases := mappingValue(root, "testcases")
+	if testcases == nil {
+		return ts
+	}
+	for _, tcNode := range testcases.Content {
+		tc := TestCaseInventory{
+			Name:      scalarValue(mappingValue(tcNode, "name")),
+			ID:        scalarValue(mappingValue(tcNode, "id")),
+			Tags:      scalarValues(mappingValue(tcNode, "tags")),
+			Executors: []ExecutorUsage{},
+		}
+		steps := mappingValue(tcNode, "steps")
+		if steps != nil {
+			tc.Steps = len(steps.Content)
+			used := map[string]bool{}
+			for _, step := range steps.Content {
+				name := scalarValue(mappingValue(step, "type"))
+				if name == "" && mappingValue(step, "script") != nil {
+					name = "exec"
+				}
+				if name == "" || used[name] {
+					continue
+				}
+				used[name] = true
+				tc.Executors = append(tc.Executors, ExecutorUsage{Name: name, Kind: v.executorKind(name, workdir, userExecutors)})
+			}
+		}
+		ts.TestCases = append(ts.TestCases, tc)
+	}
+	return ts
+}
+
+// executorKind returns whether an executor is a builtin, a user executor or a plugin, without loading it
+func (v *Venom) executorKind(name, workdir string, userExecutors map[string]UserExecutorInfo) string {
+	if strings.Contains(name, "{{") {
+		return ExecutorKindUnknown
+	}
+	if _, ok := v.executorsBuiltin[name]; ok {
+		return ExecutorKindBuiltin
+	}
+	if _, ok := userExecutors[name]; ok {
+		return ExecutorKindUser
+	}
+	if pluginExists(workdir, name) {
+		return ExecutorKindPlugin
+	}
+	return ExecutorKindUnknown
+}
+
+// pluginExists returns true if a plugin can be loaded for a testsuite located in workdir
+func pluginExists(workdir, name string) bool {
+	for _, p := range []string{filepath.Join(workdir, "lib", name+".so"), filepath.Join("lib", name+".so")} {
+		if fileExists(p) {
+			return true
+		}
+	}
+	return false
+}
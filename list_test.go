@@ -0,0 +1,71 @@
+package venom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestList(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "lib"), 0755))
+	userExecutor := `executor: myexecutor
+input:
+  foo: bar
+steps:
+- script: echo {{.input.foo}}
+output:
+  result: "{{.result.systemout}}"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "lib", "myexecutor.yml"), []byte(userExecutor), 0644))
+	testsuite := `name: inventory
+testcases:
+- name: first
+  id: tc-1
+  tags: [smoke, users]
+  steps:
+  - type: test
+  - type: test
+  - type: myexecutor
+- name: second
+  steps:
+  - script: echo
+  - type: unknown
+`
+	filename := filepath.Join(dir, "inventory.yml")
+	require.NoError(t, os.WriteFile(filename, []byte(testsuite), 0644))
+
+	v := New()
+	v.RegisterExecutorBuiltin("test", lintTestExecutor{})
+	inventory, err := v.List(context.Background(), []string{filename})
+	require.NoError(t, err)
+
+	require.Len(t, inventory.TestSuites, 1)
+	ts := inventory.TestSuites[0]
+	assert.Equal(t, "inventory", ts.Name)
+	require.Len(t, ts.TestCases, 2)
+	assert.Equal(t, TestCaseInventory{
+		Name:  "first",
+		ID:    "tc-1",
+		Tags:  []string{"smoke", "users"},
+		Steps: 3,
+		Executors: []ExecutorUsage{
+			{Name: "test", Kind: ExecutorKindBuiltin},
+			{Name: "myexecutor", Kind: ExecutorKindUser},
+		},
+	}, ts.TestCases[0])
+	assert.Equal(t, []ExecutorUsage{
+		{Name: "exec", Kind: ExecutorKindUnknown},
+		{Name: "unknown", Kind: ExecutorKindUnknown},
+	}, ts.TestCases[1].Executors)
+	assert.Nil(t, ts.TestCases[1].Tags)
+
+	require.Len(t, inventory.UserExecutors, 1)
+	assert.Equal(t, "myexecutor", inventory.UserExecutors[0].Name)
+	assert.Equal(t, []string{"foo"}, inventory.UserExecutors[0].Inputs)
+	assert.Equal(t, []string{"result"}, inventory.UserExecutors[0].Outputs)
+}
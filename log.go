@@ -3,6 +3,8 @@ package venom
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
@@ -16,10 +18,22 @@ func InitTestLogger(t *testing.T) {
 }
 
 var (
-	logger *logrus.Entry
+	// logger is used when the context doesn't carry the logger of a venom instance
+	logger = logrus.NewEntry(&logrus.Logger{Out: io.Discard, Formatter: new(logrus.TextFormatter), Hooks: make(logrus.LevelHooks), Level: logrus.WarnLevel, ExitFunc: os.Exit})
 	fields = []string{"testsuite", "testcase", "step", "executor"}
 )
 
+const loggerKey = ContextKey("logger")
+
+func loggerFromContext(ctx context.Context) *logrus.Entry {
+	if ctx != nil {
+		if l, ok := ctx.Value(loggerKey).(*logrus.Entry); ok {
+			return l
+		}
+	}
+	return logger
+}
+
 func fieldsFromContext(ctx context.Context, keys ...string) logrus.Fields {
 	var fields = logrus.Fields{}
 	if ctx == nil {
@@ -54,30 +68,30 @@ func HideSensitive(ctx context.Context, arg interface{}) string {
 
 func Debug(ctx context.Context, format string, args ...interface{}) {
 	fields := fieldsFromContext(ctx, fields...)
-	logger.WithFields(fields).Debugf(format, args...)
+	loggerFromContext(ctx).WithFields(fields).Debugf(format, args...)
 }
 
 func Info(ctx context.Context, format string, args ...interface{}) {
 	fields := fieldsFromContext(ctx, fields...)
-	logger.WithFields(fields).Infof(format, args...)
+	loggerFromContext(ctx).WithFields(fields).Infof(format, args...)
 }
 
 func Warn(ctx context.Context, format string, args ...interface{}) {
 	fields := fieldsFromContext(ctx, fields...)
-	logger.WithFields(fields).Warnf(format, args...)
+	loggerFromContext(ctx).WithFields(fields).Warnf(format, args...)
 }
 
 func Warning(ctx context.Context, format string, args ...interface{}) {
 	fields := fieldsFromContext(ctx, fields...)
-	logger.WithFields(fields).Warningf(format, args...)
+	loggerFromContext(ctx).WithFields(fields).Warningf(format, args...)
 }
 
 func Error(ctx context.Context, format string, args ...interface{}) {
 	fields := fieldsFromContext(ctx, fields...)
-	logger.WithFields(fields).Errorf(format, args...)
+	loggerFromContext(ctx).WithFields(fields).Errorf(format, args...)
 }
 
 func Fatal(ctx context.Context, format string, args ...interface{}) {
 	fields := fieldsFromContext(ctx, fields...)
-	logger.WithFields(fields).Fatalf(format, args...)
+	loggerFromContext(ctx).WithFields(fields).Fatalf(format, args...)
 }
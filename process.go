@@ -4,55 +4,24 @@ import (
 	"context"
 	"fmt"
 	"os"
-	"path/filepath"
 	"strings"
 	"time"
 
-	nested "github.com/antonfisher/nested-logrus-formatter"
-	"github.com/gosimple/slug"
 	"github.com/pkg/errors"
-	"github.com/sirupsen/logrus"
 )
 
 // InitLogger initializes venom logger
+//
+// Deprecated: use NewWithOptions, which initializes the logger of the venom instance, and run the testsuites with
+// the context returned by WithLogger.
 func (v *Venom) InitLogger() error {
 	v.Tests.TestSuites = []TestSuite{}
-
-	switch v.Verbose {
-	case 1:
-		logrus.SetLevel(logrus.InfoLevel)
-	case 2:
-		logrus.SetLevel(logrus.DebugLevel)
-	default:
-		logrus.SetLevel(logrus.WarnLevel)
-	}
-
 	if v.OutputDir != "" {
 		if err := os.MkdirAll(v.OutputDir, os.FileMode(0755)); err != nil {
 			return errors.Wrapf(err, "unable to create output dir")
 		}
 	}
-
-	var err error
-	var logFile = filepath.Join(v.OutputDir, computeOutputFilename("venom.log"))
-	v.LogOutput, err = os.OpenFile(logFile, os.O_CREATE|os.O_RDWR, os.FileMode(0644))
-	if err != nil {
-		return errors.Wrapf(err, "unable to write log file")
-	}
-	v.PrintlnTrace("writing " + logFile)
-	logrus.SetOutput(v.LogOutput)
-
-	logrus.SetFormatter(&nested.Formatter{
-		HideKeys:       true,
-		FieldsOrder:    []string{"testsuite", "testcase", "step", "executor"},
-		NoColors:       true,
-		NoFieldsColors: true,
-	})
-	logger = logrus.NewEntry(logrus.StandardLogger())
-
-	slug.Lowercase = false
-
-	return nil
+	return v.initLogger()
 }
 
 func computeOutputFilename(filename string) string {
@@ -84,6 +53,10 @@ func (v *Venom) Parse(ctx context.Context, path []string) error {
 	if err != nil {
 		return err
 	}
+	if v.Shard != nil {
+		filesPath = v.Shard.Select(filesPath)
+		Info(ctx, "Shard %s: %d testsuite(s) selected", v.Shard, len(filesPath))
+	}
 
 	if err := v.readFiles(ctx, filesPath); err != nil {
 		return err
@@ -174,20 +147,39 @@ func (v *Venom) Parse(ctx context.Context, path []string) error {
 }
 
 // Process runs tests suite and return a Tests result
-func (v *Venom) Process(ctx context.Context, path []string) error {
+func (v *Venom) Process(ctx context.Context, path []string) (err error) {
 	v.Tests.Status = StatusRun
 	v.Tests.Start = time.Now()
+	if v.Shard != nil {
+		v.Tests.Shard = v.Shard.String()
+	}
+	hostname, _ := os.Hostname()
+	v.initReporters()
+	v.report(func(r Reporter) { r.RunStart(ctx, &v.Tests) })
+	ctx, runSpan := v.startSpan(ctx, "venom run")
+	// the span is ended before the end of the run is reported, for the traces to be exported, or when the run fails
+	defer func() {
+		if err != nil {
+			runSpan.finish(StatusFail, err.Error())
+		}
+	}()
 	Debug(ctx, "nb testsuites: %d", len(v.Tests.TestSuites))
 	for i := range v.Tests.TestSuites {
+		ts := &v.Tests.TestSuites[i]
+		tsCtx, tsSpan := v.startSpan(ctx, ts.Name, otlpString("venom.testsuite.name", ts.Name), otlpString("code.filepath", ts.Filepath))
 
 		v.Tests.TestSuites[i].Start = time.Now()
+		v.Tests.TestSuites[i].Shard = v.Tests.Shard
+		v.Tests.TestSuites[i].Hostname = hostname
 		// ##### RUN Test Suite Here
-		if err := v.runTestSuite(ctx, &v.Tests.TestSuites[i]); err != nil {
+		if err := v.runTestSuite(tsCtx, &v.Tests.TestSuites[i]); err != nil {
 			return err
 		}
 
 		v.Tests.TestSuites[i].End = time.Now()
 		v.Tests.TestSuites[i].Duration = v.Tests.TestSuites[i].End.Sub(v.Tests.TestSuites[i].Start).Seconds()
+		tsSpan.finish(ts.Status, "", otlpString("venom.testsuite.status", string(ts.Status)))
+		v.report(func(r Reporter) { r.TestSuiteEnd(ctx, &v.Tests.TestSuites[i]) })
 	}
 	v.Tests.End = time.Now()
 	v.Tests.Duration = v.Tests.End.Sub(v.Tests.Start).Seconds()
@@ -211,6 +203,7 @@ func (v *Venom) Process(ctx context.Context, path []string) error {
 	}
 
 	Debug(ctx, "final status: %s", v.Tests.Status)
+	runSpan.finish(v.Tests.Status, "", otlpString("venom.status", string(v.Tests.Status)), otlpString("venom.shard", v.Tests.Shard))
 
-	return nil
+	return v.reportRunEnd(ctx)
 }
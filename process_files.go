@@ -15,6 +15,11 @@ import (
 	"github.com/pkg/errors"
 )
 
+// TestSuiteFiles returns the testsuite files selected by path: files, directories and glob patterns
+func TestSuiteFiles(path []string) ([]string, error) {
+	return getFilesPath(path)
+}
+
 func getFilesPath(path []string) ([]string, error) {
 	filePaths := make([]string, 0)
 
@@ -113,7 +118,7 @@ func (v *Venom) readFiles(ctx context.Context, filesPath []string) (err error) {
 
 		var testSuiteInput TestSuiteInput
 		if err := yaml.Unmarshal([]byte(content), &testSuiteInput); err != nil {
-			Error(context.Background(), "file content: %s", content)
+			Error(ctx, "file content: %s", content)
 			return errors.Wrapf(err, "error while unmarshal file %q", filePath)
 		}
 
@@ -123,9 +128,14 @@ func (v *Venom) readFiles(ctx context.Context, filesPath []string) (err error) {
 			Vars:      testSuiteInput.Vars,
 			Secrets:   testSuiteInput.Secrets,
 		}
+		lines := testCaseLines([]byte(content))
 		for i := range testSuiteInput.TestCases {
 			ts.TestCases[i] = TestCase{
 				TestCaseInput: testSuiteInput.TestCases[i],
+				DeclaredVars:  testSuiteInput.TestCases[i].Vars.Clone(),
+			}
+			if i < len(lines) {
+				ts.TestCases[i].Line = lines[i]
 			}
 		}
 		Info(ctx, "Has %d Secrets", len(ts.Secrets))
@@ -151,15 +151,27 @@ func (v *Venom) runTestCase(ctx context.Context, ts *TestSuite, tc *TestCase) {
 }
 
 func (v *Venom) processSecrets(ctx context.Context, ts *TestSuite, tc *TestCase) context.Context {
-	computedSecrets := []string{}
+	computedSecrets := testCaseSecrets(ts, tc)
+	// the values given to AddSecrets are hidden even if they are not variables
+	for k, s := range v.secrets {
+		if _, ok := tc.Vars[k]; !ok {
+			computedSecrets = append(computedSecrets, fmt.Sprint(s))
+		}
+	}
+	return context.WithValue(ctx, ContextKey("secrets"), computedSecrets)
+}
+
+// testCaseSecrets returns the values of the vars of a testcase which are secrets of its testsuite
+func testCaseSecrets(ts *TestSuite, tc *TestCase) []string {
+	secrets := []string{}
 	for k, v := range tc.Vars {
 		for _, s := range ts.Secrets {
 			if strings.Compare(k, s) == 0 {
-				computedSecrets = append(computedSecrets, fmt.Sprint(v))
+				secrets = append(secrets, fmt.Sprint(v))
 			}
 		}
 	}
-	return context.WithValue(ctx, ContextKey("secrets"), computedSecrets)
+	return secrets
 }
 
 func (v *Venom) runTestSteps(ctx context.Context, tc *TestCase, tsIn *TestStepResult) {
@@ -183,6 +195,10 @@ func (v *Venom) runTestSteps(ctx context.Context, tc *TestCase, tsIn *TestStepRe
 	var knowExecutors = map[string]struct{}{}
 	var previousStepVars = H{}
 	fromUserExecutor := tsIn != nil
+	// the iterations of a ranged step with aggregate assertions are reported once the aggregate assertions are
+	// evaluated, as they decide of the status of the iterations. They are reported as is if the steps stop before.
+	var pending []pendingStep
+	defer func() { v.reportPendingSteps(tc, tsIn, pending) }()
 
 	for stepNumber, rawStep := range tc.RawTestSteps {
 		stepVars := tc.Vars.Clone()
@@ -191,12 +207,25 @@ func (v *Venom) runTestSteps(ctx context.Context, tc *TestCase, tsIn *TestStepRe
 		stepVars.Add("venom.teststep.number", stepNumber)
 
 		ranged, err := parseRanged(ctx, rawStep, stepVars)
-		if err != nil {
+		if err != nil && v.DryRun {
+			// the range may depend on the results of the previous steps: the step is rendered once
+			Warn(ctx, "unable to parse \"range\" attribute: %v", err)
+			ranged = Range{Items: []RangeData{{}}}
+		} else if err != nil {
 			Error(ctx, "unable to parse \"range\" attribute: %v", err)
-			tsIn.appendError(err)
+			testStepResult := TestStepResult{Number: stepNumber}
+			testStepResult.appendError(err)
+			tc.TestStepResults = append(tc.TestStepResults, testStepResult)
+			if tsIn != nil {
+				tsIn.appendError(err)
+			}
 			return
 		}
 
+		firstRangedResult := len(tc.TestStepResults)
+		var aggregateAssertions []Assertion
+		var hasAggregate bool
+		var aggregateErr error
 		for rangedIndex, rangedData := range ranged.Items {
 			tc.TestStepResults = append(tc.TestStepResults, TestStepResult{})
 			tsResult := &tc.TestStepResults[len(tc.TestStepResults)-1]
@@ -208,50 +237,18 @@ func (v *Venom) runTestSteps(ctx context.Context, tc *TestCase, tsIn *TestStepRe
 				stepVars.Add("value", rangedData.Value)
 			}
 
-			vars, err := DumpStringPreserveCase(stepVars)
+			vars, content, err := interpolateStep(stepVars, rawStep)
 			if err != nil {
-				Error(ctx, "unable to dump testcase vars: %v", err)
 				tsResult.appendError(err)
+				Error(ctx, "%v", err)
 				return
 			}
 
-			for k, v := range vars {
-				content, err := interpolate.Do(v, vars)
-				if err != nil {
-					tsResult.appendError(err)
-					Error(ctx, "unable to interpolate variable %q: %v", k, err)
-					return
-				}
-				vars[k] = content
-			}
-
-			// the value of each var can contains a double-quote -> "
-			// if the value is not escaped, it will be used as is, and the json sent to unmarshall will be incorrect.
-			// This also avoids injections into the json structure of a step
-			for i := range vars {
-				if strings.Contains(vars[i], `"`) {
-					x := strconv.Quote(vars[i])
-					x = strings.TrimPrefix(x, `"`)
-					x = strings.TrimSuffix(x, `"`)
-					vars[i] = x
-				}
-			}
-
-			var content string
-			for i := 0; i < 10; i++ {
-				content, err = interpolate.Do(string(rawStep), vars)
-				if err != nil {
-					tsResult.appendError(err)
-					Error(ctx, "unable to interpolate step: %v", err)
-					return
-				}
-				if !strings.Contains(content, "{{") {
-					break
-				}
-			}
-
 			if ranged.Enabled {
 				Info(ctx, "Step #%d-%d content is: %s", stepNumber, rangedIndex, HideSensitive(ctx, content))
+				if rangedIndex == 0 && !v.DryRun {
+					aggregateAssertions, hasAggregate, aggregateErr = parseAggregateAssertions([]byte(content))
+				}
 			} else {
 				Info(ctx, "Step #%d content is: %s", stepNumber, HideSensitive(ctx, content))
 			}
@@ -292,7 +289,7 @@ func (v *Venom) runTestSteps(ctx context.Context, tc *TestCase, tsIn *TestStepRe
 				break
 			}
 
-			if e != nil {
+			if e != nil && !v.DryRun {
 				_, known := knowExecutors[e.Name()]
 				if !known {
 					ctx, err = e.Setup(ctx, tc.Vars)
@@ -311,8 +308,21 @@ func (v *Venom) runTestSteps(ctx context.Context, tc *TestCase, tsIn *TestStepRe
 				}
 			}
 
-			printStepName := v.Verbose >= 1 && !fromUserExecutor
-			v.setTestStepName(tsResult, e, step, &ranged, &rangedData, rangedIndex, printStepName)
+			v.setTestStepName(tsResult, e, step, &ranged, &rangedData, rangedIndex)
+			if !fromUserExecutor && !hasAggregate {
+				v.report(func(r Reporter) { r.StepStart(ctx, tc, tsResult) })
+			}
+
+			var debugStep *DebugStep
+			if v.Debugger != nil && !fromUserExecutor {
+				ctx, e, step, debugStep = v.debugBeforeStep(ctx, tc, tsResult, e, step, stepVars, rawStep, content)
+			}
+
+			// the steps of user executors are run in the span of the step using the user executor
+			stepCtx, stepSpan := ctx, (*span)(nil)
+			if !fromUserExecutor {
+				stepCtx, stepSpan = v.startStepSpan(ctx, tc, tsResult, e)
+			}
 
 			// ##### RUN Test Step Here
 			skip, err := parseSkip(ctx, tc, tsResult, rawStep, stepNumber)
@@ -321,21 +331,37 @@ func (v *Venom) runTestSteps(ctx context.Context, tc *TestCase, tsIn *TestStepRe
 				tsResult.Status = StatusFail
 			} else if skip {
 				tsResult.Status = StatusSkip
+			} else if v.DryRun {
+				v.dryRunStep(ctx, e, tc, tsResult, step, content)
 			} else {
-				tsResult.Start = time.Now()
-				tsResult.Status = StatusRun
-				v.RunTestStep(ctx, e, tc, tsResult, stepNumber, rangedIndex, step)
-				if len(tsResult.Errors) > 0 || !tsResult.AssertionsApplied.OK {
-					tsResult.Status = StatusFail
-				} else {
-					tsResult.Status = StatusPass
-				}
+				for {
+					tsResult.Start = time.Now()
+					tsResult.Status = StatusRun
+					v.RunTestStep(stepCtx, e, tc, tsResult, stepNumber, rangedIndex, step)
+					if len(tsResult.Errors) > 0 || !tsResult.AssertionsApplied.OK {
+						tsResult.Status = StatusFail
+					} else {
+						tsResult.Status = StatusPass
+					}
 
-				tsResult.End = time.Now()
-				tsResult.Duration = tsResult.End.Sub(tsResult.Start).Seconds()
+					tsResult.End = time.Now()
+					tsResult.Duration = tsResult.End.Sub(tsResult.Start).Seconds()
+
+					if !v.debugAfterStep(ctx, debugStep, tsResult) {
+						break
+					}
+				}
 
 				tc.testSteps = append(tc.testSteps, step)
 			}
+			report := func() {
+				if hasAggregate {
+					pending = append(pending, pendingStep{index: len(tc.TestStepResults) - 1, ctx: stepCtx, span: stepSpan})
+					return
+				}
+				endStepSpan(stepCtx, stepSpan, tsResult)
+				v.reportTestStepResult(ctx, tc, tsResult, tsIn)
+			}
 
 			var isRequired bool
 
@@ -348,19 +374,25 @@ func (v *Venom) runTestSteps(ctx context.Context, tc *TestCase, tsIn *TestStepRe
 
 				if isRequired {
 					failure := newFailure(ctx, *tc, stepNumber, rangedIndex, "", fmt.Errorf("At least one required assertion failed, skipping remaining steps"))
+					failure.Type = FailureTypeAssertion
 					tsResult.appendFailure(*failure)
-					v.printTestStepResult(tc, tsResult, tsIn, stepNumber, true)
+					report()
 					return
 				}
-				v.printTestStepResult(tc, tsResult, tsIn, stepNumber, false)
+				report()
 				continue
 			}
-			v.printTestStepResult(tc, tsResult, tsIn, stepNumber, false)
+			report()
 
 			allVars := tc.Vars.Clone()
 			allVars.AddAll(tsResult.ComputedVars.Clone())
 
-			assign, _, err := processVariableAssignments(ctx, tc.Name, allVars, rawStep)
+			var assign H
+			if v.DryRun {
+				assign, err = dryRunVariableAssignments(rawStep)
+			} else {
+				assign, _, err = processVariableAssignments(ctx, tc.Name, allVars, rawStep)
+			}
 			if err != nil {
 				tsResult.appendError(err)
 				Error(ctx, "unable to process variable assignments: %v", err)
@@ -370,11 +402,68 @@ func (v *Venom) runTestSteps(ctx context.Context, tc *TestCase, tsIn *TestStepRe
 			tc.computedVars.AddAll(assign)
 			previousStepVars.AddAll(assign)
 		}
+
+		if aggregateErr != nil {
+			Error(ctx, "%v", aggregateErr)
+			aggregateResult := TestStepResult{Number: stepNumber, RangedEnable: true}
+			aggregateResult.appendError(aggregateErr)
+			tc.TestStepResults = append(tc.TestStepResults, aggregateResult)
+			v.reportTestStepResult(ctx, tc, &aggregateResult, tsIn)
+		} else if hasAggregate && len(tc.TestStepResults) > firstRangedResult {
+			aggregateResult := v.runAggregateAssertions(ctx, tc, aggregateAssertions, stepNumber, tc.TestStepResults[firstRangedResult:])
+			v.reportPendingSteps(tc, tsIn, pending)
+			pending = nil
+			if tsIn == nil {
+				v.report(func(r Reporter) { r.StepStart(ctx, tc, aggregateResult) })
+			}
+			tc.TestStepResults = append(tc.TestStepResults, *aggregateResult)
+			v.reportTestStepResult(ctx, tc, aggregateResult, tsIn)
+		}
 	}
 }
 
+// interpolateStep resolves the variables of a raw step. It returns the variables used, and the interpolated step.
+func interpolateStep(stepVars H, rawStep json.RawMessage) (map[string]string, string, error) {
+	vars, err := DumpStringPreserveCase(stepVars)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "unable to dump testcase vars")
+	}
+
+	for k, v := range vars {
+		content, err := interpolate.Do(v, vars)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "unable to interpolate variable %q", k)
+		}
+		vars[k] = content
+	}
+
+	// the value of each var can contains a double-quote -> "
+	// if the value is not escaped, it will be used as is, and the json sent to unmarshall will be incorrect.
+	// This also avoids injections into the json structure of a step
+	for i := range vars {
+		if strings.Contains(vars[i], `"`) {
+			x := strconv.Quote(vars[i])
+			x = strings.TrimPrefix(x, `"`)
+			x = strings.TrimSuffix(x, `"`)
+			vars[i] = x
+		}
+	}
+
+	var content string
+	for i := 0; i < 10; i++ {
+		content, err = interpolate.Do(string(rawStep), vars)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "unable to interpolate step")
+		}
+		if !strings.Contains(content, "{{") {
+			break
+		}
+	}
+	return vars, content, nil
+}
+
 // Set test step name (defaults to executor name, excepted if it got a "name" attribute. in range, also print key)
-func (v *Venom) setTestStepName(ts *TestStepResult, e ExecutorRunner, step TestStep, ranged *Range, rangedData *RangeData, rangedIndex int, print bool) {
+func (v *Venom) setTestStepName(ts *TestStepResult, e ExecutorRunner, step TestStep, ranged *Range, rangedData *RangeData, rangedIndex int) {
 	name := e.Name()
 	if value, ok := step["name"]; ok {
 		switch value := value.(type) {
@@ -386,46 +475,16 @@ func (v *Venom) setTestStepName(ts *TestStepResult, e ExecutorRunner, step TestS
 		name = fmt.Sprintf("%s (range=%s)", name, rangedData.Key)
 	}
 	ts.Name = name
-
-	if print {
-		v.Print(" \t\t• %s", ts.Name)
-	}
 }
 
-// Print a single step result (if verbosity is enabled)
-func (v *Venom) printTestStepResult(tc *TestCase, ts *TestStepResult, tsIn *TestStepResult, stepNumber int, mustAssertionFailed bool) {
+// Report a single step result: the results of the steps of a user executor are added to the step using it
+func (v *Venom) reportTestStepResult(ctx context.Context, tc *TestCase, ts *TestStepResult, tsIn *TestStepResult) {
 	if tsIn != nil {
 		tsIn.appendFailure(ts.Errors...)
-	} else if v.Verbose >= 1 {
-		if len(ts.Errors) > 0 {
-			v.Println(" %s", Red(StatusFail))
-			for _, i := range ts.ComputedInfo {
-				v.Println(" \t\t  %s %s", Cyan("[info]"), Cyan(i))
-			}
-			for _, f := range ts.Errors {
-				v.Println(" \t\t  %s", Yellow(f.Value))
-			}
-			if mustAssertionFailed {
-				skipped := len(tc.RawTestSteps) - stepNumber - 1
-				if skipped == 1 {
-					v.Println(" \t\t  %s", Gray(fmt.Sprintf("%d other step was skipped", skipped)))
-				} else {
-					v.Println(" \t\t  %s", Gray(fmt.Sprintf("%d other steps were skipped", skipped)))
-				}
-			}
-		} else if ts.Status == StatusSkip {
-			v.Println(" %s", Gray(StatusSkip))
-		} else {
-			if ts.Retries == 0 {
-				v.Println(" %s", Green(StatusPass))
-			} else {
-				v.Println(" %s (after %d attempts)", Green(StatusPass), ts.Retries)
-			}
-			for _, i := range ts.ComputedInfo {
-				v.Println(" \t\t  %s %s", Cyan("[info]"), Cyan(i))
-			}
-		}
+		tsIn.Warnings = append(tsIn.Warnings, ts.Warnings...)
+		return
 	}
+	v.report(func(r Reporter) { r.StepEnd(ctx, tc, ts) })
 }
 
 // Parse and format skip conditional
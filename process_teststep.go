@@ -8,7 +8,6 @@ import (
 	"path"
 	"time"
 
-	"github.com/gosimple/slug"
 	"github.com/ovh/cds/sdk/interpolate"
 )
 
@@ -61,13 +60,14 @@ func (v *Venom) RunTestStep(ctx context.Context, e ExecutorRunner, tc *TestCase,
 			if oDir == "" {
 				oDir = "."
 			}
-			filename := path.Join(oDir, fmt.Sprintf("%s.%s.step.%d.%d.dump.json", slug.Make(StringVarFromCtx(ctx, "venom.testsuite.shortName")), slug.Make(tc.Name), stepNumber, rangedIndex))
+			filename := path.Join(oDir, fmt.Sprintf("%s.%s.step.%d.%d.dump.json", slugify(StringVarFromCtx(ctx, "venom.testsuite.shortName")), slugify(tc.Name), stepNumber, rangedIndex))
 
 			if err := os.WriteFile(filename, []byte(HideSensitive(ctx, string(output))), 0644); err != nil {
 				Error(ctx, "Error while creating file %s: %v", filename, err)
 				return
 			}
 			tc.computedVerbose = append(tc.computedVerbose, fmt.Sprintf("writing %s", filename))
+			tsResult.DumpFile = filename
 		}
 
 		for ninfo, i := range e.Info() {
@@ -80,7 +80,7 @@ func (v *Venom) RunTestStep(ctx context.Context, e ExecutorRunner, tc *TestCase,
 				continue
 			}
 			filename := StringVarFromCtx(ctx, "venom.testsuite.filename")
-			lineNumber := findLineNumber(filename, tc.originalName, stepNumber, i, ninfo+1)
+			lineNumber := findLineNumber(StringVarFromCtx(ctx, "venom.testsuite.filepath"), tc.originalName, stepNumber, i, ninfo+1)
 			if lineNumber > 0 {
 				info += fmt.Sprintf(" (%s:%d)", filename, lineNumber)
 			} else if tc.IsExecutor {
@@ -119,19 +119,24 @@ func (v *Venom) RunTestStep(ctx context.Context, e ExecutorRunner, tc *TestCase,
 		}
 		if len(failures) > 0 {
 			failure := newFailure(ctx, *tc, stepNumber, rangedIndex, "", fmt.Errorf("retry conditions not fulfilled, skipping %d remaining retries", e.Retry()-tsResult.Retries))
+			failure.Type = FailureTypeAssertion
 			tsResult.Errors = append(tsResult.Errors, *failure)
 			break
 		}
 	}
 
 	if tsResult.Retries > 1 && len(assertRes.errors) > 0 {
-		tsResult.appendFailure(Failure{Value: fmt.Sprintf("It's a failure after %d attempts", tsResult.Retries)})
+		tsResult.appendFailure(Failure{Type: FailureTypeAssertion, Value: fmt.Sprintf("It's a failure after %d attempts", tsResult.Retries)})
 	}
 
 	if len(assertRes.errors) > 0 {
 		tsResult.appendFailure(assertRes.errors...)
 	}
 
+	if len(assertRes.warnings) > 0 {
+		tsResult.Warnings = append(tsResult.Warnings, assertRes.warnings...)
+	}
+
 	tsResult.Systemerr += assertRes.systemerr + "\n"
 	tsResult.Systemout += assertRes.systemout + "\n"
 }
@@ -172,6 +177,6 @@ func (v *Venom) runTestStepExecutor(ctx context.Context, e ExecutorRunner, tc *T
 	case result := <-ch:
 		return result, nil
 	case <-ctxTimeout.Done():
-		return nil, fmt.Errorf("Timeout after %d second(s)", e.Timeout())
+		return nil, fmt.Errorf(timeoutPrefix+"%d second(s)", e.Timeout())
 	}
 }
@@ -5,9 +5,9 @@ import (
 	"fmt"
 	"os"
 	"runtime/pprof"
+	"sort"
 	"time"
 
-	"github.com/gosimple/slug"
 	"github.com/ovh/cds/sdk/interpolate"
 	"github.com/pkg/errors"
 )
@@ -50,6 +50,15 @@ func (v *Venom) runTestSuite(ctx context.Context, ts *TestSuite) error {
 		ts.Vars.Add("venom.executable", exePath)
 	}
 
+	secrets := make([]string, 0, len(v.secrets))
+	for k := range v.secrets {
+		if !isInList(k, ts.Secrets) {
+			secrets = append(secrets, k)
+		}
+	}
+	sort.Strings(secrets)
+	ts.Secrets = append(ts.Secrets, secrets...)
+
 	ts.Vars.Add("venom.outputdir", v.OutputDir)
 	ts.Vars.Add("venom.libdir", v.LibDir)
 	ts.Vars.Add("venom.testsuite", ts.Name)
@@ -69,6 +78,7 @@ func (v *Venom) runTestSuite(ctx context.Context, ts *TestSuite) error {
 	for _, v := range ts.Secrets {
 		Info(ctx, "secret  %+v", v)
 	}
+	v.report(func(r Reporter) { r.TestSuiteStart(ctx, ts) })
 	// ##### RUN Test Cases Here
 	v.runTestCases(ctx, ts)
 
@@ -100,35 +110,37 @@ func (v *Venom) runTestSuite(ctx context.Context, ts *TestSuite) error {
 }
 
 func (v *Venom) runTestCases(ctx context.Context, ts *TestSuite) {
-	verboseReport := v.Verbose >= 1
-
-	v.Println(" • %s (%s)", ts.Name, ts.Filepath)
-
 	for i := range ts.TestCases {
 		tc := &ts.TestCases[i]
 		tc.IsEvaluated = true
-		v.Print(" \t• %s", tc.Name)
+		v.report(func(r Reporter) { r.TestCaseStart(ctx, ts, tc) })
+		tcCtx, tcSpan := v.startSpan(ctx, tc.Name,
+			otlpString("venom.testcase.name", tc.Name),
+			otlpString("venom.testcase.id", tc.ID),
+			otlpStrings("venom.testcase.tags", tc.Tags),
+			otlpString("code.filepath", ts.Filepath),
+			otlpInt("code.lineno", tc.Line),
+		)
 		var hasFailure bool
-		var hasRanged bool
 		var hasSkipped = len(tc.Skipped) > 0
 		if !hasSkipped {
 			start := time.Now()
 			tc.Start = start
 			ts.Status = StatusRun
-			if verboseReport || hasRanged {
-				v.Print("\n")
-			}
 			// ##### RUN Test Case Here
-			v.runTestCase(ctx, ts, tc)
+			if v.TestCaseWrapper != nil {
+				v.TestCaseWrapper(tcCtx, ts, tc, func(ctx context.Context) {
+					v.runTestCase(ctx, ts, tc)
+				})
+			} else {
+				v.runTestCase(tcCtx, ts, tc)
+			}
 			tc.End = time.Now()
 			tc.Duration = tc.End.Sub(tc.Start).Seconds()
 		}
 
 		skippedSteps := 0
 		for _, testStepResult := range tc.TestStepResults {
-			if testStepResult.RangedEnable {
-				hasRanged = true
-			}
 			if testStepResult.Status == StatusFail {
 				hasFailure = true
 			}
@@ -145,46 +157,9 @@ func (v *Venom) runTestCases(ctx context.Context, ts *TestSuite) {
 		} else if tc.Status != StatusSkip {
 			tc.Status = StatusPass
 		}
+		tcSpan.finish(tc.Status, "", otlpString("venom.testcase.status", string(tc.Status)))
 
-		// Verbose mode already reported tests status, so just print them when non-verbose
-		indent := ""
-		if verboseReport {
-			indent = "\t  "
-			// If the testcase was entirely skipped, then the verbose mode will not have any output
-			// Print something to inform that the testcase was indeed processed although skipped
-			if len(tc.TestStepResults) == 0 {
-				v.Println("\t\t%s", Gray("• (all steps were skipped)"))
-				continue
-			}
-		} else {
-			if hasFailure {
-				v.Println(" %s", Red(StatusFail))
-			} else if tc.Status == StatusSkip {
-				v.Println(" %s", Gray(StatusSkip))
-				continue
-			} else {
-				v.Println(" %s", Green(StatusPass))
-			}
-		}
-
-		for _, i := range tc.computedVerbose {
-			v.PrintlnIndentedTrace(i, indent)
-		}
-
-		// Verbose mode already reported failures, so just print them when non-verbose
-		if !verboseReport && hasFailure {
-			for _, testStepResult := range tc.TestStepResults {
-				if len(testStepResult.ComputedInfo) > 0 || len(testStepResult.Errors) > 0 {
-					v.Println(" \t\t• %s", testStepResult.Name)
-					for _, f := range testStepResult.ComputedInfo {
-						v.Println(" \t\t  %s", Cyan(f))
-					}
-					for _, f := range testStepResult.Errors {
-						v.Println(" \t\t  %s", Yellow(f.Value))
-					}
-				}
-			}
-		}
+		v.report(func(r Reporter) { r.TestCaseEnd(ctx, ts, tc) })
 
 		if v.StopOnFailure {
 			for _, testStepResult := range tc.TestStepResults {
@@ -210,7 +185,7 @@ func (v *Venom) parseTestCases(ts *TestSuite) ([]string, []string, error) {
 	for i := range ts.TestCases {
 		tc := &ts.TestCases[i]
 		tc.originalName = tc.Name
-		tc.Name = slug.Make(tc.Name)
+		tc.Name = slugify(tc.Name)
 		tc.Vars = ts.Vars.Clone()
 		tc.Vars.Add("venom.testcase", tc.Name)
 
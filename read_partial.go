@@ -37,7 +37,7 @@ func getVarFromPartialYML(ctx context.Context, btesIn []byte) (H, error) {
 	var partial partialVars
 	if len(btes) > 0 {
 		if err := yaml.Unmarshal([]byte(btes), &partial); err != nil {
-			Error(context.Background(), "file content: %s", string(btes))
+			Error(ctx, "file content: %s", string(btes))
 			return nil, errors.Wrapf(err, "error while unmarshal - see venom.log")
 		}
 	}
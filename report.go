@@ -0,0 +1,148 @@
+package venom
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ReadReport reads a JSON report, written by venom run --format json
+func ReadReport(filename string) (Tests, error) {
+	var tests Tests
+	btes, err := os.ReadFile(filename)
+	if err != nil {
+		return tests, errors.Wrapf(err, "unable to read report %q", filename)
+	}
+	if err := json.Unmarshal(btes, &tests); err != nil {
+		return tests, errors.Wrapf(err, "unable to parse report %q, it must be a JSON report", filename)
+	}
+	return tests, nil
+}
+
+// ReadReports reads JSON reports. Filenames can be glob patterns.
+func ReadReports(filenames ...string) ([]Tests, error) {
+	var reports []Tests
+	for _, pattern := range filenames {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid report pattern %q", pattern)
+		}
+		if len(matches) == 0 {
+			// reported as a missing file
+			matches = []string{pattern}
+		}
+		for _, f := range matches {
+			tests, err := ReadReport(f)
+			if err != nil {
+				return nil, err
+			}
+			reports = append(reports, tests)
+		}
+	}
+	return reports, nil
+}
+
+// MergeReports merges reports into a single one. When a testsuite is found in several reports, the last run is kept.
+// The counters, durations and statuses are computed again from the testcases.
+func MergeReports(reports ...Tests) Tests {
+	var merged Tests
+	index := map[string]int{}
+	shards := map[string]struct{}{}
+	for _, r := range reports {
+		if r.Shard != "" {
+			shards[r.Shard] = struct{}{}
+		}
+		for _, ts := range r.TestSuites {
+			if ts.Shard == "" {
+				ts.Shard = r.Shard
+			}
+			key := testSuiteKey(ts)
+			if i, ok := index[key]; ok {
+				if !ts.Start.Before(merged.TestSuites[i].Start) {
+					merged.TestSuites[i] = ts
+				}
+				continue
+			}
+			index[key] = len(merged.TestSuites)
+			merged.TestSuites = append(merged.TestSuites, ts)
+		}
+	}
+
+	var sum float64
+	for i := range merged.TestSuites {
+		ts := &merged.TestSuites[i]
+		computeTestSuiteStatus(ts)
+		switch ts.Status {
+		case StatusFail:
+			merged.NbTestsuitesFail++
+		case StatusSkip:
+			merged.NbTestsuitesSkip++
+		default:
+			merged.NbTestsuitesPass++
+		}
+
+		sum += ts.Duration
+		if !ts.Start.IsZero() && (merged.Start.IsZero() || ts.Start.Before(merged.Start)) {
+			merged.Start = ts.Start
+		}
+		if ts.End.After(merged.End) {
+			merged.End = ts.End
+		}
+	}
+
+	switch {
+	case merged.NbTestsuitesFail > 0:
+		merged.Status = StatusFail
+	case merged.NbTestsuitesSkip > 0 && merged.NbTestsuitesSkip == len(merged.TestSuites):
+		merged.Status = StatusSkip
+	default:
+		merged.Status = StatusPass
+	}
+
+	// the testsuites may have been run in parallel: the duration is the time elapsed from the first start to the last end
+	if !merged.Start.IsZero() && !merged.End.IsZero() {
+		merged.Duration = merged.End.Sub(merged.Start).Seconds()
+	} else {
+		merged.Duration = sum
+	}
+
+	var shardIDs []string
+	for s := range shards {
+		shardIDs = append(shardIDs, s)
+	}
+	sort.Strings(shardIDs)
+	merged.Shard = strings.Join(shardIDs, ",")
+	return merged
+}
+
+// computeTestSuiteStatus computes the counters and the status of a testsuite from its testcases
+func computeTestSuiteStatus(ts *TestSuite) {
+	ts.NbTestcasesFail, ts.NbTestcasesPass, ts.NbTestcasesSkip = 0, 0, 0
+	for _, tc := range ts.TestCases {
+		switch tc.Status {
+		case StatusFail:
+			ts.NbTestcasesFail++
+		case StatusSkip:
+			ts.NbTestcasesSkip++
+		case StatusPass:
+			ts.NbTestcasesPass++
+		}
+	}
+
+	switch {
+	case ts.NbTestcasesFail > 0:
+		ts.Status = StatusFail
+	case ts.NbTestcasesSkip > 0 && ts.NbTestcasesSkip == len(ts.TestCases):
+		ts.Status = StatusSkip
+	default:
+		ts.Status = StatusPass
+	}
+
+	if ts.Duration == 0 && !ts.Start.IsZero() && ts.End.After(ts.Start) {
+		ts.Duration = ts.End.Sub(ts.Start).Seconds()
+	}
+}
@@ -0,0 +1,179 @@
+package venom
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Levels of the durations compared between two runs
+const (
+	CompareLevelTestSuite = "testsuite"
+	CompareLevelTestCase  = "testcase"
+	CompareLevelStep      = "step"
+)
+
+// CompareOptions are the options of a comparison between two runs
+type CompareOptions struct {
+	// Threshold is the increase of a duration, in percent, above which it is a regression
+	Threshold float64
+	// MinDuration is the duration, in seconds, below which the durations are not compared
+	MinDuration float64
+}
+
+// Comparison is the difference between a baseline run and a current run
+type Comparison struct {
+	NewFailures         []ComparedTestCase   `json:"newFailures"`
+	NewPasses           []ComparedTestCase   `json:"newPasses"`
+	Appeared            []ComparedTestCase   `json:"appeared"`
+	Disappeared         []ComparedTestCase   `json:"disappeared"`
+	DurationRegressions []DurationRegression `json:"durationRegressions"`
+}
+
+// ComparedTestCase is a testcase whose status changed between two runs
+type ComparedTestCase struct {
+	TestSuite string `json:"testsuite"`
+	TestCase  string `json:"testcase"`
+	Baseline  Status `json:"baseline,omitempty"`
+	Current   Status `json:"current,omitempty"`
+}
+
+// DurationRegression is a testsuite, a testcase or a step which is slower than in the baseline run
+type DurationRegression struct {
+	Level     string  `json:"level"`
+	TestSuite string  `json:"testsuite"`
+	TestCase  string  `json:"testcase,omitempty"`
+	Step      string  `json:"step,omitempty"`
+	Baseline  float64 `json:"baseline"`
+	Current   float64 `json:"current"`
+	// Increase is the increase of the duration, in percent
+	Increase float64 `json:"increase"`
+}
+
+// Name returns the path of the item which is slower: testsuite > testcase > step
+func (r DurationRegression) Name() string {
+	name := r.TestSuite
+	if r.TestCase != "" {
+		name += " > " + r.TestCase
+	}
+	if r.Step != "" {
+		name += " > " + r.Step
+	}
+	return name
+}
+
+// HasRegressions returns true if testcases newly fail, or if durations regressed
+func (c Comparison) HasRegressions() bool {
+	return len(c.NewFailures) > 0 || len(c.DurationRegressions) > 0
+}
+
+// CompareReports compares a current run with a baseline run. The testsuites are matched by filepath, the testcases
+// by name and the steps by number.
+func CompareReports(baseline, current Tests, opts CompareOptions) Comparison {
+	var c Comparison
+
+	baselineSuites := map[string]*TestSuite{}
+	for i := range baseline.TestSuites {
+		baselineSuites[testSuiteKey(baseline.TestSuites[i])] = &baseline.TestSuites[i]
+	}
+	currentSuites := map[string]bool{}
+
+	for i := range current.TestSuites {
+		ts := &current.TestSuites[i]
+		key := testSuiteKey(*ts)
+		currentSuites[key] = true
+		bts, ok := baselineSuites[key]
+		if !ok {
+			for _, tc := range ts.TestCases {
+				c.Appeared = append(c.Appeared, ComparedTestCase{TestSuite: ts.Name, TestCase: tc.Name, Current: tc.Status})
+			}
+			continue
+		}
+		c.compareDuration(opts, DurationRegression{Level: CompareLevelTestSuite, TestSuite: ts.Name}, bts.Duration, ts.Duration)
+
+		baselineCases := map[string]*TestCase{}
+		for j := range bts.TestCases {
+			baselineCases[bts.TestCases[j].Name] = &bts.TestCases[j]
+		}
+		currentCases := map[string]bool{}
+		for j := range ts.TestCases {
+			tc := &ts.TestCases[j]
+			currentCases[tc.Name] = true
+			compared := ComparedTestCase{TestSuite: ts.Name, TestCase: tc.Name, Current: tc.Status}
+			btc, ok := baselineCases[tc.Name]
+			if !ok {
+				c.Appeared = append(c.Appeared, compared)
+				continue
+			}
+			compared.Baseline = btc.Status
+			switch {
+			case tc.Status == StatusFail && btc.Status != StatusFail:
+				c.NewFailures = append(c.NewFailures, compared)
+			case tc.Status == StatusPass && btc.Status == StatusFail:
+				c.NewPasses = append(c.NewPasses, compared)
+			}
+			c.compareDuration(opts, DurationRegression{Level: CompareLevelTestCase, TestSuite: ts.Name, TestCase: compared.TestCase}, btc.Duration, tc.Duration)
+			c.compareSteps(opts, ts.Name, compared.TestCase, btc.TestStepResults, tc.TestStepResults)
+		}
+		for _, btc := range bts.TestCases {
+			if !currentCases[btc.Name] {
+				c.Disappeared = append(c.Disappeared, ComparedTestCase{TestSuite: bts.Name, TestCase: btc.Name, Baseline: btc.Status})
+			}
+		}
+	}
+
+	for _, bts := range baseline.TestSuites {
+		if currentSuites[testSuiteKey(bts)] {
+			continue
+		}
+		for _, btc := range bts.TestCases {
+			c.Disappeared = append(c.Disappeared, ComparedTestCase{TestSuite: bts.Name, TestCase: btc.Name, Baseline: btc.Status})
+		}
+	}
+	return c
+}
+
+func (c *Comparison) compareSteps(opts CompareOptions, testsuite, testcase string, baseline, current []TestStepResult) {
+	baselineSteps := map[string]TestStepResult{}
+	for _, s := range baseline {
+		baselineSteps[stepKey(s)] = s
+	}
+	for _, s := range current {
+		bs, ok := baselineSteps[stepKey(s)]
+		if !ok {
+			continue
+		}
+		name := s.Name
+		if name == "" {
+			name = fmt.Sprintf("step #%d", s.Number)
+		}
+		c.compareDuration(opts, DurationRegression{Level: CompareLevelStep, TestSuite: testsuite, TestCase: testcase, Step: name}, bs.Duration, s.Duration)
+	}
+}
+
+func (c *Comparison) compareDuration(opts CompareOptions, r DurationRegression, baseline, current float64) {
+	if baseline <= 0 || current < opts.MinDuration {
+		return
+	}
+	increase := (current - baseline) / baseline * 100
+	if increase <= opts.Threshold {
+		return
+	}
+	r.Baseline = baseline
+	r.Current = current
+	r.Increase = increase
+	c.DurationRegressions = append(c.DurationRegressions, r)
+}
+
+func testSuiteKey(ts TestSuite) string {
+	if ts.Filepath == "" {
+		return ts.Name
+	}
+	return filepath.Clean(ts.Filepath)
+}
+
+func stepKey(s TestStepResult) string {
+	if s.RangedEnable {
+		return fmt.Sprintf("%d-%d", s.Number, s.RangedIndex)
+	}
+	return fmt.Sprintf("%d", s.Number)
+}
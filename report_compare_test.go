@@ -0,0 +1,52 @@
+package venom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareReports(t *testing.T) {
+	baseline := Tests{TestSuites: []TestSuite{
+		{Name: "api", Filepath: "tests/api.yml", Duration: 10, TestCases: []TestCase{
+			{TestCaseInput: TestCaseInput{Name: "create"}, Status: StatusPass, Duration: 2,
+				TestStepResults: []TestStepResult{{Name: "http", Number: 0, Duration: 1}, {Name: "exec", Number: 1, Duration: 1}}},
+			{TestCaseInput: TestCaseInput{Name: "delete"}, Status: StatusFail, Duration: 1},
+			{TestCaseInput: TestCaseInput{Name: "removed"}, Status: StatusPass},
+		}},
+		{Name: "old", Filepath: "tests/old.yml", TestCases: []TestCase{{TestCaseInput: TestCaseInput{Name: "tc"}, Status: StatusPass}}},
+	}}
+	current := Tests{TestSuites: []TestSuite{
+		{Name: "api", Filepath: "./tests/api.yml", Duration: 11, TestCases: []TestCase{
+			{TestCaseInput: TestCaseInput{Name: "create"}, Status: StatusFail, Duration: 2.1,
+				TestStepResults: []TestStepResult{{Name: "http", Number: 0, Duration: 2}, {Name: "exec", Number: 1, Duration: 0.05}}},
+			{TestCaseInput: TestCaseInput{Name: "delete"}, Status: StatusPass, Duration: 0.05},
+			{TestCaseInput: TestCaseInput{Name: "added"}, Status: StatusPass},
+		}},
+		{Name: "new", Filepath: "tests/new.yml", TestCases: []TestCase{{TestCaseInput: TestCaseInput{Name: "tc"}, Status: StatusFail}}},
+	}}
+
+	c := CompareReports(baseline, current, CompareOptions{Threshold: 20, MinDuration: 0.1})
+
+	assert.Equal(t, []ComparedTestCase{{TestSuite: "api", TestCase: "create", Baseline: StatusPass, Current: StatusFail}}, c.NewFailures)
+	assert.Equal(t, []ComparedTestCase{{TestSuite: "api", TestCase: "delete", Baseline: StatusFail, Current: StatusPass}}, c.NewPasses)
+	assert.Equal(t, []ComparedTestCase{
+		{TestSuite: "api", TestCase: "added", Current: StatusPass},
+		{TestSuite: "new", TestCase: "tc", Current: StatusFail},
+	}, c.Appeared)
+	assert.Equal(t, []ComparedTestCase{
+		{TestSuite: "api", TestCase: "removed", Baseline: StatusPass},
+		{TestSuite: "old", TestCase: "tc", Baseline: StatusPass},
+	}, c.Disappeared)
+
+	// only the http step is slower than the threshold: the testsuite and the testcase are in the threshold
+	require.Len(t, c.DurationRegressions, 1)
+	r := c.DurationRegressions[0]
+	assert.Equal(t, CompareLevelStep, r.Level)
+	assert.Equal(t, "api > create > http", r.Name())
+	assert.Equal(t, 100.0, r.Increase)
+	assert.True(t, c.HasRegressions())
+
+	assert.False(t, CompareReports(baseline, baseline, CompareOptions{}).HasRegressions())
+}
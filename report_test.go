@@ -0,0 +1,80 @@
+package venom
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeReports(t *testing.T) {
+	start := time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC)
+	shard1 := Tests{
+		Shard:            "1/2",
+		NbTestsuitesPass: 42,
+		TestSuites: []TestSuite{
+			{Name: "a", Filepath: "tests/a.yml", Start: start, End: start.Add(2 * time.Second), Duration: 2,
+				TestCases: []TestCase{{Status: StatusPass}, {Status: StatusFail}}},
+			{Name: "b", Filepath: "tests/b.yml", Start: start, End: start.Add(time.Second), Duration: 1,
+				TestCases: []TestCase{{Status: StatusSkip}}},
+		},
+	}
+	shard2 := Tests{
+		Shard: "2/2",
+		TestSuites: []TestSuite{
+			{Name: "c", Filepath: "tests/c.yml", Start: start.Add(time.Second), End: start.Add(4 * time.Second), Duration: 3,
+				TestCases: []TestCase{{Status: StatusPass}}},
+		},
+	}
+
+	merged := MergeReports(shard1, shard2)
+	require.Len(t, merged.TestSuites, 3)
+	assert.Equal(t, StatusFail, merged.Status)
+	assert.Equal(t, 1, merged.NbTestsuitesFail)
+	assert.Equal(t, 1, merged.NbTestsuitesPass)
+	assert.Equal(t, 1, merged.NbTestsuitesSkip)
+	assert.Equal(t, start, merged.Start)
+	assert.Equal(t, start.Add(4*time.Second), merged.End)
+	assert.Equal(t, 4.0, merged.Duration)
+	assert.Equal(t, "1/2,2/2", merged.Shard)
+
+	a := merged.TestSuites[0]
+	assert.Equal(t, StatusFail, a.Status)
+	assert.Equal(t, 1, a.NbTestcasesFail)
+	assert.Equal(t, 1, a.NbTestcasesPass)
+	assert.Equal(t, "1/2", a.Shard)
+	assert.Equal(t, StatusSkip, merged.TestSuites[1].Status)
+	assert.Equal(t, "2/2", merged.TestSuites[2].Shard)
+
+	// a testsuite run again replaces the previous run
+	rerun := Tests{TestSuites: []TestSuite{
+		{Name: "a", Filepath: "./tests/a.yml", Start: start.Add(10 * time.Second), End: start.Add(11 * time.Second),
+			TestCases: []TestCase{{Status: StatusPass}, {Status: StatusPass}}},
+	}}
+	merged = MergeReports(shard1, shard2, rerun)
+	require.Len(t, merged.TestSuites, 3)
+	assert.Equal(t, StatusPass, merged.TestSuites[0].Status)
+	assert.Equal(t, 1.0, merged.TestSuites[0].Duration)
+	assert.Equal(t, StatusPass, merged.Status)
+}
+
+func TestReadReports(t *testing.T) {
+	dir := t.TempDir()
+	for i, name := range []string{"a", "b"} {
+		btes, err := json.Marshal(Tests{TestSuites: []TestSuite{{Name: name, Filepath: name + ".yml", Duration: float64(i)}}})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(filepath.Join(dir, "test_results_"+name+".json"), btes, 0644))
+	}
+
+	reports, err := ReadReports(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, reports, 2)
+	assert.Equal(t, "a", reports[0].TestSuites[0].Name)
+
+	_, err = ReadReports(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
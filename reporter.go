@@ -0,0 +1,199 @@
+package venom
+
+import (
+	"context"
+	"fmt"
+)
+
+// Reporter receives the events of a run, in the order of the run. The console output and the reports written in the
+// output directory are reporters: other reporters can be added with AddReporter, they all receive the same events.
+type Reporter interface {
+	RunStart(ctx context.Context, tests *Tests)
+	TestSuiteStart(ctx context.Context, ts *TestSuite)
+	TestCaseStart(ctx context.Context, ts *TestSuite, tc *TestCase)
+	// StepStart is called before a step is run, once its name is known. The steps of user executors are not reported.
+	StepStart(ctx context.Context, tc *TestCase, step *TestStepResult)
+	StepEnd(ctx context.Context, tc *TestCase, step *TestStepResult)
+	TestCaseEnd(ctx context.Context, ts *TestSuite, tc *TestCase)
+	TestSuiteEnd(ctx context.Context, ts *TestSuite)
+	// RunEnd is called once all the testsuites are run. Its error fails the run.
+	RunEnd(ctx context.Context, tests *Tests) error
+}
+
+// BaseReporter ignores all the events: embed it to implement only some methods of Reporter
+type BaseReporter struct{}
+
+func (BaseReporter) RunStart(ctx context.Context, tests *Tests)                        {}
+func (BaseReporter) TestSuiteStart(ctx context.Context, ts *TestSuite)                 {}
+func (BaseReporter) TestCaseStart(ctx context.Context, ts *TestSuite, tc *TestCase)    {}
+func (BaseReporter) StepStart(ctx context.Context, tc *TestCase, step *TestStepResult) {}
+func (BaseReporter) StepEnd(ctx context.Context, tc *TestCase, step *TestStepResult)   {}
+func (BaseReporter) TestCaseEnd(ctx context.Context, ts *TestSuite, tc *TestCase)      {}
+func (BaseReporter) TestSuiteEnd(ctx context.Context, ts *TestSuite)                   {}
+func (BaseReporter) RunEnd(ctx context.Context, tests *Tests) error                    { return nil }
+
+// AddReporter adds a reporter, which receives the events of the next runs
+func (v *Venom) AddReporter(r Reporter) {
+	v.reporters = append(v.reporters, r)
+}
+
+// initReporters sets the reporters of a run: the console, the reports of the output directory, the summary file, the
+// traces, the metrics, and the added reporters
+func (v *Venom) initReporters() {
+	v.activeReporters = []Reporter{&consoleReporter{v: v}}
+	v.tracer = nil
+	if v.OutputDir != "" && !v.DryRun {
+		v.activeReporters = append(v.activeReporters, v.outputReporters()...)
+	}
+	if v.SummaryFile != "" && !v.DryRun {
+		v.activeReporters = append(v.activeReporters, &summaryReporter{v: v, filename: v.SummaryFile})
+	}
+	if (v.OTLPEndpoint != "" || v.OTLPFile != "") && !v.DryRun {
+		v.tracer = newTracer()
+		v.activeReporters = append(v.activeReporters, &tracingReporter{v: v})
+	}
+	if (v.MetricsFile != "" || v.Pushgateway != "") && !v.DryRun {
+		v.activeReporters = append(v.activeReporters, &metricsReporter{v: v})
+	}
+	v.activeReporters = append(v.activeReporters, v.reporters...)
+}
+
+func (v *Venom) report(f func(r Reporter)) {
+	for _, r := range v.activeReporters {
+		f(r)
+	}
+}
+
+// reportRunEnd calls RunEnd on all the reporters, and returns the first error
+func (v *Venom) reportRunEnd(ctx context.Context) error {
+	var firstErr error
+	for _, r := range v.activeReporters {
+		if err := r.RunEnd(ctx, &v.Tests); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// consoleReporter prints the progress of a run with PrintFunc
+type consoleReporter struct {
+	BaseReporter
+	v *Venom
+}
+
+func (r *consoleReporter) TestSuiteStart(ctx context.Context, ts *TestSuite) {
+	r.v.Println(" • %s (%s)", ts.Name, ts.Filepath)
+}
+
+func (r *consoleReporter) TestCaseStart(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	r.v.Print(" \t• %s", tc.Name)
+	if r.v.Verbose >= 1 && len(tc.Skipped) == 0 {
+		r.v.Print("\n")
+	}
+}
+
+func (r *consoleReporter) StepStart(ctx context.Context, tc *TestCase, step *TestStepResult) {
+	if r.v.Verbose >= 1 {
+		r.v.Print(" \t\t• %s", step.Name)
+	}
+}
+
+func (r *consoleReporter) StepEnd(ctx context.Context, tc *TestCase, ts *TestStepResult) {
+	v := r.v
+	if v.Verbose < 1 {
+		return
+	}
+	if len(ts.Errors) > 0 {
+		v.Println(" %s", Red(StatusFail))
+		for _, i := range ts.ComputedInfo {
+			v.Println(" \t\t  %s %s", Cyan("[info]"), Cyan(i))
+		}
+		var mustAssertionFailed bool
+		for _, f := range ts.Errors {
+			v.Println(" \t\t  %s", Yellow(f.Value))
+			if f.Diff != nil {
+				v.Println("%s", FormatDiff(f.Diff, " \t\t    ", true))
+			}
+			mustAssertionFailed = mustAssertionFailed || f.AssertionRequired
+		}
+		if mustAssertionFailed {
+			skipped := len(tc.RawTestSteps) - ts.Number - 1
+			if skipped == 1 {
+				v.Println(" \t\t  %s", Gray(fmt.Sprintf("%d other step was skipped", skipped)))
+			} else {
+				v.Println(" \t\t  %s", Gray(fmt.Sprintf("%d other steps were skipped", skipped)))
+			}
+		}
+	} else if ts.Status == StatusSkip {
+		v.Println(" %s", Gray(StatusSkip))
+	} else {
+		if ts.Retries == 0 {
+			v.Println(" %s", Green(StatusPass))
+		} else {
+			v.Println(" %s (after %d attempts)", Green(StatusPass), ts.Retries)
+		}
+		for _, i := range ts.ComputedInfo {
+			v.Println(" \t\t  %s %s", Cyan("[info]"), Cyan(i))
+		}
+	}
+	for _, w := range ts.Warnings {
+		v.Println(" \t\t  %s %s", Yellow("[warn]"), Yellow(w.Value))
+	}
+}
+
+func (r *consoleReporter) TestCaseEnd(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	v := r.v
+	verboseReport := v.Verbose >= 1
+
+	// Verbose mode already reported tests status, so just print them when non-verbose
+	indent := ""
+	if verboseReport {
+		indent = "\t  "
+		// If the testcase was entirely skipped, then the verbose mode will not have any output
+		// Print something to inform that the testcase was indeed processed although skipped
+		if len(tc.TestStepResults) == 0 {
+			v.Println("\t\t%s", Gray("• (all steps were skipped)"))
+			return
+		}
+	} else {
+		if tc.Status == StatusFail {
+			v.Println(" %s", Red(StatusFail))
+		} else if tc.Status == StatusSkip {
+			v.Println(" %s", Gray(StatusSkip))
+			return
+		} else {
+			v.Println(" %s", Green(StatusPass))
+		}
+	}
+
+	for _, i := range tc.computedVerbose {
+		v.PrintlnIndentedTrace(i, indent)
+	}
+
+	// Verbose mode already reported warnings, so just print them when non-verbose
+	if !verboseReport {
+		for _, testStepResult := range tc.TestStepResults {
+			for _, w := range testStepResult.Warnings {
+				v.Println(" \t\t  %s %s", Yellow("[warn]"), Yellow(w.Value))
+			}
+		}
+	}
+
+	// Verbose mode already reported failures, so just print them when non-verbose
+	if !verboseReport && tc.Status == StatusFail {
+		for _, testStepResult := range tc.TestStepResults {
+			if len(testStepResult.ComputedInfo) > 0 || len(testStepResult.Errors) > 0 {
+				v.Println(" \t\t• %s", testStepResult.Name)
+				for _, f := range testStepResult.ComputedInfo {
+					v.Println(" \t\t  %s", Cyan(f))
+				}
+				for _, f := range testStepResult.Errors {
+					v.Println(" \t\t  %s", Yellow(f.Value))
+					if f.Diff != nil {
+						v.Println("%s", FormatDiff(f.Diff, " \t\t    ", true))
+					}
+				}
+			}
+		}
+	}
+}
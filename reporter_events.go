@@ -0,0 +1,138 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// Types of the events of a run
+const (
+	EventRunStart       = "run_start"
+	EventTestSuiteStart = "testsuite_start"
+	EventTestCaseStart  = "testcase_start"
+	EventStepStart      = "step_start"
+	EventStepEnd        = "step_end"
+	EventTestCaseEnd    = "testcase_end"
+	EventTestSuiteEnd   = "testsuite_end"
+	EventRunEnd         = "run_end"
+)
+
+// Event is an event of a run, as sent by the reporters of NewEventReporter
+type Event struct {
+	Type      string     `json:"type"`
+	Time      time.Time  `json:"time"`
+	TestSuite string     `json:"testsuite,omitempty"`
+	Filepath  string     `json:"filepath,omitempty"`
+	TestCase  string     `json:"testcase,omitempty"`
+	Step      *EventStep `json:"step,omitempty"`
+	Status    Status     `json:"status,omitempty"`
+	// Duration is the duration, in seconds, of what ended
+	Duration float64 `json:"duration,omitempty"`
+	// Failures are the failures of a step, with the secrets hidden
+	Failures []string `json:"failures,omitempty"`
+	// Error is the error which stopped a run
+	Error string `json:"error,omitempty"`
+}
+
+// EventStep is the step of an event
+type EventStep struct {
+	Name        string `json:"name"`
+	Number      int    `json:"number"`
+	RangedIndex int    `json:"rangedIndex,omitempty"`
+}
+
+// NewEventReporter returns a reporter which sends the events of a run to send
+func NewEventReporter(send func(e Event)) Reporter {
+	return &eventReporter{send: send}
+}
+
+// NewNDJSONReporter returns a reporter which writes the events of a run to w, as newline delimited JSON
+func NewNDJSONReporter(w io.Writer) Reporter {
+	r := &ndjsonReporter{enc: json.NewEncoder(w)}
+	r.eventReporter.send = r.write
+	return r
+}
+
+type eventReporter struct {
+	send func(e Event)
+}
+
+func (r *eventReporter) RunStart(ctx context.Context, tests *Tests) {
+	r.send(Event{Type: EventRunStart, Time: time.Now()})
+}
+
+func (r *eventReporter) TestSuiteStart(ctx context.Context, ts *TestSuite) {
+	r.send(Event{Type: EventTestSuiteStart, Time: time.Now(), TestSuite: ts.Name, Filepath: ts.Filepath})
+}
+
+func (r *eventReporter) TestCaseStart(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	r.send(Event{Type: EventTestCaseStart, Time: time.Now(), TestSuite: ts.Name, TestCase: tc.Name})
+}
+
+func (r *eventReporter) StepStart(ctx context.Context, tc *TestCase, step *TestStepResult) {
+	r.send(Event{Type: EventStepStart, Time: time.Now(), TestSuite: testSuiteName(tc), TestCase: tc.Name, Step: newEventStep(step)})
+}
+
+func (r *eventReporter) StepEnd(ctx context.Context, tc *TestCase, step *TestStepResult) {
+	e := Event{
+		Type:      EventStepEnd,
+		Time:      time.Now(),
+		TestSuite: testSuiteName(tc),
+		TestCase:  tc.Name,
+		Step:      newEventStep(step),
+		Status:    step.Status,
+		Duration:  step.Duration,
+	}
+	for _, f := range step.Errors {
+		e.Failures = append(e.Failures, HideSensitive(ctx, f.Value))
+	}
+	r.send(e)
+}
+
+func (r *eventReporter) TestCaseEnd(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	r.send(Event{Type: EventTestCaseEnd, Time: time.Now(), TestSuite: ts.Name, TestCase: tc.Name, Status: tc.Status, Duration: tc.Duration})
+}
+
+func (r *eventReporter) TestSuiteEnd(ctx context.Context, ts *TestSuite) {
+	r.send(Event{Type: EventTestSuiteEnd, Time: time.Now(), TestSuite: ts.Name, Filepath: ts.Filepath, Status: ts.Status, Duration: ts.Duration})
+}
+
+func (r *eventReporter) RunEnd(ctx context.Context, tests *Tests) error {
+	r.send(Event{Type: EventRunEnd, Time: time.Now(), Status: tests.Status, Duration: tests.Duration})
+	return nil
+}
+
+func newEventStep(step *TestStepResult) *EventStep {
+	return &EventStep{Name: step.Name, Number: step.Number, RangedIndex: step.RangedIndex}
+}
+
+type ndjsonReporter struct {
+	eventReporter
+	mutex sync.Mutex
+	enc   *json.Encoder
+	err   error
+}
+
+func (r *ndjsonReporter) write(e Event) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if r.err == nil {
+		r.err = r.enc.Encode(e)
+	}
+}
+
+func (r *ndjsonReporter) RunEnd(ctx context.Context, tests *Tests) error {
+	r.eventReporter.RunEnd(ctx, tests) // nolint
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	return r.err
+}
+
+// testSuiteName returns the name of the testsuite of a testcase, as set in its variables
+func testSuiteName(tc *TestCase) string {
+	name, _ := tc.TestSuiteVars["venom.testsuite"].(string)
+	return name
+}
@@ -0,0 +1,128 @@
+package venom
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const reporterTestSuite = `name: greet
+testcases:
+- name: hello
+  steps:
+  - type: echo
+    value: "hello {{.who}}"
+    assertions:
+    - out ShouldEqual "hello world"
+- name: goodbye
+  steps:
+  - type: echo
+    value: "goodbye {{.who}}"
+    assertions:
+    - out ShouldEqual "hello {{.who}}"
+`
+
+type recordTestReporter struct {
+	BaseReporter
+	events []string
+	err    error
+}
+
+func (r *recordTestReporter) RunStart(ctx context.Context, tests *Tests) {
+	r.events = append(r.events, "run_start")
+}
+
+func (r *recordTestReporter) TestSuiteStart(ctx context.Context, ts *TestSuite) {
+	r.events = append(r.events, "testsuite_start "+ts.Name)
+}
+
+func (r *recordTestReporter) TestCaseStart(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	r.events = append(r.events, "testcase_start "+tc.Name)
+}
+
+func (r *recordTestReporter) StepEnd(ctx context.Context, tc *TestCase, step *TestStepResult) {
+	r.events = append(r.events, "step_end "+string(step.Status))
+}
+
+func (r *recordTestReporter) TestCaseEnd(ctx context.Context, ts *TestSuite, tc *TestCase) {
+	r.events = append(r.events, "testcase_end "+tc.Name+" "+string(tc.Status))
+}
+
+func (r *recordTestReporter) RunEnd(ctx context.Context, tests *Tests) error {
+	r.events = append(r.events, "run_end "+string(tests.Status))
+	return r.err
+}
+
+func runReporterTest(t *testing.T, reporters ...Reporter) (*Tests, error) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "greet.yml"), []byte(reporterTestSuite), 0644))
+	return Run(context.Background(), Options{
+		Paths:     []string{filepath.Join(dir, "greet.yml")},
+		Variables: map[string]interface{}{"who": "world"},
+		Executors: map[string]Executor{"echo": &debugTestExecutor{}},
+		Reporters: reporters,
+		LogOutput: io.Discard,
+	})
+}
+
+func TestReporter(t *testing.T) {
+	r1 := &recordTestReporter{}
+	r2 := &recordTestReporter{err: errors.New("unable to report")}
+	_, err := runReporterTest(t, r1, r2)
+	assert.EqualError(t, err, "unable to report")
+
+	expected := []string{
+		"run_start",
+		"testsuite_start greet",
+		"testcase_start hello",
+		"step_end PASS",
+		"testcase_end hello PASS",
+		"testcase_start goodbye",
+		"step_end FAIL",
+		"testcase_end goodbye FAIL",
+		"run_end FAIL",
+	}
+	assert.Equal(t, expected, r1.events)
+	assert.Equal(t, expected, r2.events)
+}
+
+func TestNDJSONReporter(t *testing.T) {
+	var buf bytes.Buffer
+	_, err := runReporterTest(t, NewNDJSONReporter(&buf))
+	require.NoError(t, err)
+
+	var events []Event
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var e Event
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &e))
+		events = append(events, e)
+	}
+	require.Len(t, events, 12)
+	assert.Equal(t, EventRunStart, events[0].Type)
+	assert.Equal(t, EventTestSuiteStart, events[1].Type)
+	assert.Equal(t, "greet.yml", filepath.Base(events[1].Filepath))
+
+	stepEnd := events[8]
+	assert.Equal(t, EventStepEnd, stepEnd.Type)
+	assert.Equal(t, "greet", stepEnd.TestSuite)
+	assert.Equal(t, "goodbye", stepEnd.TestCase)
+	require.NotNil(t, stepEnd.Step)
+	assert.Equal(t, 0, stepEnd.Step.Number)
+	assert.Equal(t, StatusFail, stepEnd.Status)
+	require.Len(t, stepEnd.Failures, 1)
+
+	assert.Equal(t, EventTestSuiteEnd, events[10].Type)
+	assert.Equal(t, StatusFail, events[10].Status)
+	assert.Equal(t, EventRunEnd, events[11].Type)
+	assert.Equal(t, StatusFail, events[11].Status)
+}
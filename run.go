@@ -0,0 +1,228 @@
+package venom
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	nested "github.com/antonfisher/nested-logrus-formatter"
+	"github.com/gosimple/slug"
+	"github.com/gosimple/unidecode"
+	"github.com/pkg/errors"
+	"github.com/sirupsen/logrus"
+)
+
+// Options are the options of a run of testsuites
+type Options struct {
+	// Paths are the files, directories and glob patterns of the testsuites. Defaults to the current directory.
+	Paths []string
+	// Variables are given to all the testsuites
+	Variables map[string]interface{}
+	// Secrets are variables hidden in the logs and in the reports
+	Secrets map[string]interface{}
+	// Executors are the builtin executors, by name. Use executors.Registry to get the executors of venom.
+	Executors map[string]Executor
+
+	LibDir        string
+	OutputDir     string
+	OutputFormat  string
+	HtmlReport    bool
+	StopOnFailure bool
+	Verbose       int
+	DryRun        bool
+	Shard         *Shard
+	Debugger      Debugger
+	JUnitPerStep  bool
+	// HtmlHistory is the number of previous runs shown in the HTML report, see Venom.HtmlHistory
+	HtmlHistory int
+	// OutputFormat is a comma separated list of formats: xml,json,tap. OutputMode is OutputModePerSuite, the default,
+	// to write a file per testsuite and per format in OutputDir, or OutputModeAggregate to write a file per format.
+	OutputMode string
+	// SummaryFile, if set, is a file the markdown summary of the run is appended to, like $GITHUB_STEP_SUMMARY
+	SummaryFile string
+	// OTLPEndpoint and OTLPFile export the traces of the run, see Venom.OTLPEndpoint and Venom.OTLPFile
+	OTLPEndpoint string
+	OTLPFile     string
+	// MetricsFile and Pushgateway export the Prometheus metrics of the run, see Venom.MetricsFile and Venom.Pushgateway
+	MetricsFile string
+	Pushgateway string
+
+	// Reporters receive the events of the run, in addition to the console and to the reports of OutputDir
+	Reporters []Reporter
+	// TestCaseWrapper, if set, is called to run each testcase, see Venom.TestCaseWrapper
+	TestCaseWrapper func(ctx context.Context, ts *TestSuite, tc *TestCase, run func(ctx context.Context))
+
+	// Output receives the progress of the run. Nothing is printed if it is nil.
+	Output io.Writer
+	// LogOutput receives the logs. If it is nil, the logs are written in a venom.log file in OutputDir, or in the
+	// current directory without OutputDir. Use io.Discard to drop them.
+	LogOutput io.Writer
+}
+
+// Run runs testsuites and returns their results. Reports are written in OutputDir, if set. Run doesn't change any
+// global state and never exits: it can be called several times, and concurrently, from a Go program.
+func Run(ctx context.Context, opts Options) (*Tests, error) {
+	v, err := NewWithOptions(opts)
+	if err != nil {
+		return nil, err
+	}
+	defer v.Close() // nolint
+	return v.Run(ctx, opts.Paths)
+}
+
+// NewWithOptions instantiates a venom configured with options, with its own logger
+func NewWithOptions(opts Options) (*Venom, error) {
+	v := New()
+	for name, e := range opts.Executors {
+		v.RegisterExecutorBuiltin(name, e)
+	}
+	v.AddVariables(opts.Variables)
+	// secrets are variables too, hidden in the logs and in the reports
+	v.AddVariables(opts.Secrets)
+	v.AddSecrets(opts.Secrets)
+	v.LibDir = opts.LibDir
+	v.OutputDir = opts.OutputDir
+	if opts.OutputFormat != "" {
+		if _, err := ParseFormats(opts.OutputFormat); err != nil {
+			return nil, err
+		}
+		v.OutputFormat = opts.OutputFormat
+	}
+	switch opts.OutputMode {
+	case "", OutputModePerSuite, OutputModeAggregate:
+		v.OutputMode = opts.OutputMode
+	default:
+		return nil, fmt.Errorf("invalid output mode %q: must be %s or %s", opts.OutputMode, OutputModePerSuite, OutputModeAggregate)
+	}
+	v.HtmlReport = opts.HtmlReport
+	v.HtmlHistory = opts.HtmlHistory
+	v.JUnitPerStep = opts.JUnitPerStep
+	v.SummaryFile = opts.SummaryFile
+	v.OTLPEndpoint = opts.OTLPEndpoint
+	v.OTLPFile = opts.OTLPFile
+	v.MetricsFile = opts.MetricsFile
+	v.Pushgateway = opts.Pushgateway
+	v.StopOnFailure = opts.StopOnFailure
+	v.Verbose = opts.Verbose
+	v.DryRun = opts.DryRun
+	v.Shard = opts.Shard
+	v.Debugger = opts.Debugger
+	v.TestCaseWrapper = opts.TestCaseWrapper
+	for _, r := range opts.Reporters {
+		v.AddReporter(r)
+	}
+
+	output := opts.Output
+	if output == nil {
+		output = io.Discard
+	}
+	v.PrintFunc = func(format string, a ...interface{}) (int, error) {
+		return fmt.Fprintf(output, format, a...)
+	}
+
+	if v.OutputDir != "" {
+		if err := os.MkdirAll(v.OutputDir, os.FileMode(0755)); err != nil {
+			return nil, errors.Wrapf(err, "unable to create output dir")
+		}
+	}
+
+	v.LogOutput = opts.LogOutput
+	if err := v.initLogger(); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// initLogger initializes the logger of the venom instance. Without LogOutput, the logs are written in a venom.log
+// file in OutputDir.
+func (v *Venom) initLogger() error {
+	if v.LogOutput == nil {
+		logFile := filepath.Join(v.OutputDir, computeOutputFilename("venom.log"))
+		f, err := os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(0644))
+		if err != nil {
+			return errors.Wrapf(err, "unable to write log file")
+		}
+		v.LogOutput = f
+		v.PrintlnTrace("writing " + logFile)
+	}
+	v.logger = newLogger(v.LogOutput, v.Verbose)
+	return nil
+}
+
+// Run parses and runs testsuites. The reporters write the reports in the output directory.
+func (v *Venom) Run(ctx context.Context, paths []string) (*Tests, error) {
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+	ctx = v.WithLogger(ctx)
+
+	if err := v.Parse(ctx, paths); err != nil {
+		return nil, err
+	}
+	// the reports are written by the reporters at the end of the process
+	if err := v.Process(ctx, paths); err != nil {
+		return &v.Tests, err
+	}
+
+	if v.DryRun {
+		if err := v.OutputDryRun(); err != nil {
+			return &v.Tests, err
+		}
+	}
+	return &v.Tests, nil
+}
+
+// Close closes the log file opened by NewWithOptions
+func (v *Venom) Close() error {
+	if c, ok := v.LogOutput.(io.Closer); ok && v.LogOutput != os.Stdout && v.LogOutput != os.Stderr {
+		return c.Close()
+	}
+	return nil
+}
+
+// WithLogger returns a context in which venom and the executors log with the logger of this venom instance
+func (v *Venom) WithLogger(ctx context.Context) context.Context {
+	if v.logger == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, loggerKey, v.logger)
+}
+
+func newLogger(output io.Writer, verbose int) *logrus.Entry {
+	l := logrus.New()
+	l.SetOutput(output)
+	switch verbose {
+	case 1:
+		l.SetLevel(logrus.InfoLevel)
+	case 2:
+		l.SetLevel(logrus.DebugLevel)
+	default:
+		l.SetLevel(logrus.WarnLevel)
+	}
+	l.SetFormatter(&nested.Formatter{
+		HideKeys:       true,
+		FieldsOrder:    []string{"testsuite", "testcase", "step", "executor"},
+		NoColors:       true,
+		NoFieldsColors: true,
+	})
+	return logrus.NewEntry(l)
+}
+
+var (
+	slugSubstitutions      = map[rune]string{'&': "and", '@': "at"}
+	slugNonAuthorizedChars = regexp.MustCompile("[^a-zA-Z0-9-_]")
+	slugMultipleDashes     = regexp.MustCompile("-+")
+)
+
+// slugify returns the slug of a testcase name, like slug.Make, but keeps its case. slug.Lowercase is not used, as it
+// would change the slugs of the whole program venom is embedded in.
+func slugify(s string) string {
+	s = unidecode.Unidecode(slug.SubstituteRune(strings.TrimSpace(s), slugSubstitutions))
+	s = slugNonAuthorizedChars.ReplaceAllString(s, "-")
+	s = slugMultipleDashes.ReplaceAllString(s, "-")
+	return strings.Trim(s, "-_")
+}
@@ -0,0 +1,115 @@
+package venom
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gosimple/slug"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRun(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "greet.yml"), []byte(`name: greet
+testcases:
+- name: hello
+  steps:
+  - type: echo
+    value: "hello {{.who}}"
+    assertions:
+    - out ShouldEqual "hello world"
+- name: goodbye
+  steps:
+  - type: echo
+    value: "goodbye {{.who}}"
+    assertions:
+    - out ShouldEqual "hello world"
+`), 0644))
+
+	e := &debugTestExecutor{}
+	var output bytes.Buffer
+	outputDir := filepath.Join(dir, "results")
+	tests, err := Run(context.Background(), Options{
+		Paths:        []string{filepath.Join(dir, "*.yml")},
+		Variables:    map[string]interface{}{"who": "world"},
+		Executors:    map[string]Executor{"echo": e},
+		OutputDir:    outputDir,
+		OutputFormat: "json",
+		Output:       &output,
+		LogOutput:    io.Discard,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, e.runs)
+	assert.Equal(t, StatusFail, tests.Status)
+	require.Len(t, tests.TestSuites, 1)
+	require.Len(t, tests.TestSuites[0].TestCases, 2)
+	assert.Equal(t, StatusPass, tests.TestSuites[0].TestCases[0].Status)
+	assert.Equal(t, StatusFail, tests.TestSuites[0].TestCases[1].Status)
+	assert.Contains(t, output.String(), "greet")
+
+	reports, err := ReadReports(filepath.Join(outputDir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, StatusFail, reports[0].Status)
+	assert.NoFileExists(t, filepath.Join(outputDir, "venom.log"))
+}
+
+func TestInitLogger(t *testing.T) {
+	var logs bytes.Buffer
+	v := New()
+	v.OutputDir = filepath.Join(t.TempDir(), "out")
+	v.LogOutput = &logs
+	require.NoError(t, v.InitLogger())
+	assert.DirExists(t, v.OutputDir)
+
+	Warn(v.WithLogger(context.Background()), "logged by the venom instance")
+	assert.Contains(t, logs.String(), "logged by the venom instance")
+}
+
+func Test_slugify(t *testing.T) {
+	assert.Equal(t, "Create-User-and-Check-it", slugify(" Create User & Check it! "))
+	assert.Equal(t, "cafe-at-home", slugify("café @ home"))
+	// the slugs of the program venom is embedded in are not changed
+	assert.Equal(t, "create-user", slug.Make("Create User"))
+}
+
+func TestRunSecrets(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "login.yml"), []byte(`name: login
+testcases:
+- name: login
+  steps:
+  - type: echo
+    value: "{{.password}}"
+    assertions:
+    - out ShouldEqual s3cr3t
+`), 0644))
+
+	var logs bytes.Buffer
+	tests, err := Run(context.Background(), Options{
+		Paths:     []string{filepath.Join(dir, "login.yml")},
+		Secrets:   map[string]interface{}{"password": "s3cr3t"},
+		Executors: map[string]Executor{"echo": &debugTestExecutor{}},
+		OutputDir: dir,
+		Verbose:   2,
+		LogOutput: &logs,
+	})
+	require.NoError(t, err)
+	// the secrets of the options are variables, hidden in the logs
+	assert.Equal(t, StatusPass, tests.Status)
+	assert.NotContains(t, logs.String(), "s3cr3t")
+
+	// AddSecrets only hides values
+	v := New()
+	v.AddSecrets(map[string]interface{}{"password": "s3cr3t"})
+	assert.NotContains(t, v.variables, "password")
+	ts := &TestSuite{}
+	tc := &TestCase{TestCaseInput: TestCaseInput{Vars: H{"token": "s3cr3t"}}}
+	assert.Equal(t, "token=__hidden__", HideSensitive(v.processSecrets(context.Background(), ts, tc), "token=s3cr3t"))
+}
@@ -0,0 +1,195 @@
+package venom
+
+import (
+	"fmt"
+	"sort"
+)
+
+// JSONSchemaDraft is the JSON Schema version used by JSONSchema
+const JSONSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// JSONSchema returns a JSON Schema of the testsuite files. Steps are validated against a definition
+// for each registered executor, computed from its struct tags, and for each user executor found for
+// a testsuite located in workdir.
+func (v *Venom) JSONSchema(workdir string) map[string]interface{} {
+	definitions := map[string]interface{}{
+		"assertions": map[string]interface{}{
+			"type":  "array",
+			"items": map[string]interface{}{"$ref": "#/definitions/assertion"},
+		},
+		"assertion": map[string]interface{}{
+			"oneOf": []interface{}{
+				map[string]interface{}{
+					"type":        "string",
+					"description": "An assertion, such as: result.code ShouldEqual 0",
+				},
+				map[string]interface{}{
+					"type": "object",
+					"properties": map[string]interface{}{
+						"and":      map[string]interface{}{"$ref": "#/definitions/assertions"},
+						"or":       map[string]interface{}{"$ref": "#/definitions/assertions"},
+						"xor":      map[string]interface{}{"$ref": "#/definitions/assertions"},
+						"not":      map[string]interface{}{"$ref": "#/definitions/assertions"},
+						"severity": map[string]interface{}{"enum": []string{SeverityError, SeverityWarning}},
+					},
+					"minProperties":        1,
+					"additionalProperties": false,
+				},
+			},
+		},
+		"conditions": map[string]interface{}{
+			"oneOf": []interface{}{
+				map[string]interface{}{"type": "string"},
+				map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			},
+		},
+		"testcase": map[string]interface{}{
+			"type": "object",
+			"properties": map[string]interface{}{
+				"name":  map[string]interface{}{"type": "string"},
+				"id":    map[string]interface{}{"type": "string"},
+				"vars":  map[string]interface{}{"type": "object"},
+				"tags":  map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+				"skip":  map[string]interface{}{"$ref": "#/definitions/conditions"},
+				"steps": map[string]interface{}{"type": "array", "items": map[string]interface{}{"$ref": "#/definitions/step"}},
+			},
+		},
+	}
+
+	names := []string{}
+	branches := []interface{}{}
+	addExecutor := func(name, description string, properties map[string]interface{}) {
+		for k, p := range stepKeywordsSchema() {
+			properties[k] = p
+		}
+		ref := "executor." + name
+		definitions[ref] = map[string]interface{}{
+			"type":                 "object",
+			"description":          description,
+			"properties":           properties,
+			"additionalProperties": false,
+		}
+		names = append(names, name)
+		branches = append(branches, map[string]interface{}{
+			"if": map[string]interface{}{
+				"properties": map[string]interface{}{"type": map[string]interface{}{"const": name}},
+				"required":   []string{"type"},
+			},
+			"then": map[string]interface{}{"$ref": "#/definitions/" + ref},
+		})
+	}
+
+	builtins := make([]string, 0, len(v.executorsBuiltin))
+	for name := range v.executorsBuiltin {
+		builtins = append(builtins, name)
+	}
+	sort.Strings(builtins)
+	for _, name := range builtins {
+		properties := map[string]interface{}{}
+		for _, f := range ExecutorFields(v.executorsBuiltin[name]) {
+			for _, n := range append([]string{f.Name}, f.Aliases...) {
+				properties[n] = fieldSchema(f.Type)
+			}
+		}
+		addExecutor(name, fmt.Sprintf("%s executor", name), properties)
+	}
+
+	for _, ux := range v.UserExecutorsInfo(workdir) {
+		if _, ok := v.executorsBuiltin[ux.Name]; ok {
+			continue
+		}
+		properties := map[string]interface{}{}
+		for _, i := range ux.Inputs {
+			properties[i] = map[string]interface{}{}
+		}
+		addExecutor(ux.Name, fmt.Sprintf("user executor defined in %s", ux.Filename), properties)
+	}
+
+	// a step with a script and without type is run by the exec executor
+	if _, ok := v.executorsBuiltin["exec"]; ok {
+		branches = append(branches, map[string]interface{}{
+			"if": map[string]interface{}{
+				"not":      map[string]interface{}{"required": []string{"type"}},
+				"required": []string{"script"},
+			},
+			"then": map[string]interface{}{"$ref": "#/definitions/executor.exec"},
+		})
+	}
+
+	step := map[string]interface{}{
+		"type":       "object",
+		"properties": stepKeywordsSchema(),
+	}
+	// plugins are not known statically, so any type is accepted but known executors are suggested
+	step["properties"].(map[string]interface{})["type"] = map[string]interface{}{
+		"anyOf": []interface{}{
+			map[string]interface{}{"enum": names},
+			map[string]interface{}{"type": "string"},
+		},
+	}
+	if len(branches) > 0 {
+		step["allOf"] = branches
+	}
+	definitions["step"] = step
+
+	return map[string]interface{}{
+		"$schema":     JSONSchemaDraft,
+		"title":       "Venom testsuite",
+		"type":        "object",
+		"definitions": definitions,
+		"properties": map[string]interface{}{
+			"name":    map[string]interface{}{"type": "string"},
+			"vars":    map[string]interface{}{"type": "object"},
+			"secrets": map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+			"testcases": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"$ref": "#/definitions/testcase"},
+			},
+		},
+	}
+}
+
+// stepKeywordsSchema returns the schema of the attributes handled by venom on every step
+func stepKeywordsSchema() map[string]interface{} {
+	integer := map[string]interface{}{"type": []string{"integer", "string"}}
+	return map[string]interface{}{
+		"type":     map[string]interface{}{"type": "string"},
+		"name":     map[string]interface{}{"type": "string"},
+		"retry":    integer,
+		"retry_if": map[string]interface{}{"$ref": "#/definitions/conditions"},
+		"delay":    integer,
+		"timeout":  integer,
+		"info":     map[string]interface{}{"$ref": "#/definitions/conditions"},
+		"range": map[string]interface{}{
+			"type": []string{"array", "object", "integer", "string"},
+		},
+		"skip": map[string]interface{}{"$ref": "#/definitions/conditions"},
+		"vars": map[string]interface{}{
+			"type": "object",
+			"additionalProperties": map[string]interface{}{
+				"type": "object",
+				"properties": map[string]interface{}{
+					"from":    map[string]interface{}{"type": "string"},
+					"regex":   map[string]interface{}{"type": "string"},
+					"default": map[string]interface{}{},
+				},
+				"required":             []string{"from"},
+				"additionalProperties": false,
+			},
+		},
+		"assertions":           map[string]interface{}{"$ref": "#/definitions/assertions"},
+		"aggregate_assertions": map[string]interface{}{"$ref": "#/definitions/assertions"},
+	}
+}
+
+// fieldSchema returns the schema of an executor attribute. Any attribute may be set with a template,
+// so non-string attributes accept strings too.
+func fieldSchema(jsonType string) map[string]interface{} {
+	switch jsonType {
+	case "":
+		return map[string]interface{}{}
+	case "string":
+		return map[string]interface{}{"type": "string"}
+	}
+	return map[string]interface{}{"type": []string{jsonType, "string"}}
+}
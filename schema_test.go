@@ -0,0 +1,32 @@
+package venom
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJSONSchema(t *testing.T) {
+	v := New()
+	v.RegisterExecutorBuiltin("test", lintTestExecutor{})
+	schema := v.JSONSchema(t.TempDir())
+
+	_, err := json.Marshal(schema)
+	require.NoError(t, err)
+	assert.Equal(t, JSONSchemaDraft, schema["$schema"])
+
+	definitions := schema["definitions"].(map[string]interface{})
+	require.Contains(t, definitions, "executor.test")
+	properties := definitions["executor.test"].(map[string]interface{})["properties"].(map[string]interface{})
+	for _, k := range append([]string{"command", "dialURL"}, StepKeywords...) {
+		assert.Contains(t, properties, k)
+	}
+
+	step := definitions["step"].(map[string]interface{})
+	branches := step["allOf"].([]interface{})
+	require.Len(t, branches, 1)
+	then := branches[0].(map[string]interface{})["then"].(map[string]interface{})
+	assert.Equal(t, "#/definitions/executor.test", then["$ref"])
+}
@@ -0,0 +1,148 @@
+package venom
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Shard selects a subset of the testsuites, to distribute them across several machines
+type Shard struct {
+	// Index is the number of the shard, from 1 to Total
+	Index int
+	Total int
+	// Timings are the durations of the testsuites, by filepath, used to balance the shards
+	Timings map[string]float64
+}
+
+// ParseShard parses a shard given as index/total, like 3/8
+func ParseShard(s string) (*Shard, error) {
+	idx := strings.Index(s, "/")
+	if idx < 0 {
+		return nil, fmt.Errorf("invalid shard %q: must be index/total, like 3/8", s)
+	}
+	index, err := strconv.Atoi(strings.TrimSpace(s[:idx]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid shard %q: index must be a number", s)
+	}
+	total, err := strconv.Atoi(strings.TrimSpace(s[idx+1:]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid shard %q: total must be a number", s)
+	}
+	if total < 1 || index < 1 || index > total {
+		return nil, fmt.Errorf("invalid shard %q: index must be between 1 and %d", s, total)
+	}
+	return &Shard{Index: index, Total: total}, nil
+}
+
+func (s Shard) String() string {
+	return fmt.Sprintf("%d/%d", s.Index, s.Total)
+}
+
+// LoadShardTimings reads the durations of the testsuites from JSON reports. Filenames can be glob patterns.
+func LoadShardTimings(filenames ...string) (map[string]float64, error) {
+	var files []string
+	for _, pattern := range filenames {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid timing file pattern %q", pattern)
+		}
+		if len(matches) == 0 {
+			// reported as a missing file
+			matches = []string{pattern}
+		}
+		files = append(files, matches...)
+	}
+
+	timings := map[string]float64{}
+	for _, f := range files {
+		btes, err := os.ReadFile(f)
+		if err != nil {
+			return nil, errors.Wrapf(err, "unable to read timing file %q", f)
+		}
+		var tests Tests
+		if err := json.Unmarshal(btes, &tests); err != nil {
+			return nil, errors.Wrapf(err, "unable to parse timing file %q, it must be a JSON report", f)
+		}
+		for _, ts := range tests.TestSuites {
+			if ts.Filepath == "" {
+				continue
+			}
+			timings[filepath.Clean(ts.Filepath)] = ts.Duration
+		}
+	}
+	return timings, nil
+}
+
+// Select returns the files of the shard, in their original order. The files are spread across the shards so that each
+// shard has about the same duration, using the timings. The files without timing are given the average duration,
+// and all the files weigh the same without timings.
+func (s Shard) Select(files []string) []string {
+	if s.Total <= 1 {
+		return files
+	}
+
+	var known float64
+	var nbKnown int
+	for _, f := range files {
+		if d, ok := s.Timings[filepath.Clean(f)]; ok {
+			known += d
+			nbKnown++
+		}
+	}
+	defaultWeight := 1.0
+	if nbKnown > 0 && known > 0 {
+		defaultWeight = known / float64(nbKnown)
+	}
+
+	type weightedFile struct {
+		name   string
+		weight float64
+	}
+	weighted := make([]weightedFile, 0, len(files))
+	for _, f := range files {
+		w, ok := s.Timings[filepath.Clean(f)]
+		if !ok {
+			w = defaultWeight
+		}
+		weighted = append(weighted, weightedFile{name: f, weight: w})
+	}
+	// the longest testsuites are placed first, each one on the least loaded shard
+	sort.SliceStable(weighted, func(i, j int) bool {
+		if weighted[i].weight != weighted[j].weight {
+			return weighted[i].weight > weighted[j].weight
+		}
+		return weighted[i].name < weighted[j].name
+	})
+
+	loads := make([]float64, s.Total)
+	counts := make([]int, s.Total)
+	selected := map[string]bool{}
+	for _, f := range weighted {
+		shard := 0
+		for i := range loads {
+			if loads[i] < loads[shard] || (loads[i] == loads[shard] && counts[i] < counts[shard]) {
+				shard = i
+			}
+		}
+		loads[shard] += f.weight
+		counts[shard]++
+		if shard == s.Index-1 {
+			selected[f.name] = true
+		}
+	}
+
+	var res []string
+	for _, f := range files {
+		if selected[f] {
+			res = append(res, f)
+		}
+	}
+	return res
+}
@@ -0,0 +1,59 @@
+package venom
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseShard(t *testing.T) {
+	s, err := ParseShard("3/8")
+	require.NoError(t, err)
+	assert.Equal(t, 3, s.Index)
+	assert.Equal(t, 8, s.Total)
+	assert.Equal(t, "3/8", s.String())
+
+	for _, invalid := range []string{"3", "a/8", "3/b", "0/8", "9/8", "1/0"} {
+		_, err := ParseShard(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestShardSelect(t *testing.T) {
+	files := []string{"a.yml", "b.yml", "c.yml", "d.yml", "e.yml", "f.yml", "g.yml"}
+
+	// without timings, the shards have the same number of files
+	var all []string
+	for i := 1; i <= 3; i++ {
+		selected := Shard{Index: i, Total: 3}.Select(files)
+		assert.Equal(t, selected, Shard{Index: i, Total: 3}.Select(files))
+		assert.True(t, len(selected) == 2 || len(selected) == 3, selected)
+		all = append(all, selected...)
+	}
+	assert.ElementsMatch(t, files, all)
+
+	// with timings, the shards have about the same duration
+	timings := map[string]float64{"a.yml": 10, "b.yml": 1, "c.yml": 1, "d.yml": 1, "e.yml": 1, "f.yml": 1}
+	assert.Equal(t, []string{"a.yml"}, Shard{Index: 1, Total: 2, Timings: timings}.Select(files))
+	assert.Equal(t, []string{"b.yml", "c.yml", "d.yml", "e.yml", "f.yml", "g.yml"}, Shard{Index: 2, Total: 2, Timings: timings}.Select(files))
+}
+
+func TestLoadShardTimings(t *testing.T) {
+	dir := t.TempDir()
+	report := Tests{TestSuites: []TestSuite{{Filepath: "./tests/a.yml", Duration: 4.2}, {Filepath: "tests/b.yml", Duration: 1}}}
+	btes, err := json.Marshal(report)
+	require.NoError(t, err)
+	filename := filepath.Join(dir, "report.json")
+	require.NoError(t, os.WriteFile(filename, btes, 0644))
+
+	timings, err := LoadShardTimings(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"tests/a.yml": 4.2, "tests/b.yml": 1}, timings)
+
+	_, err = LoadShardTimings(filepath.Join(dir, "missing.json"))
+	assert.Error(t, err)
+}
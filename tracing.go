@@ -0,0 +1,393 @@
+package venom
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const spanKey = ContextKey("span")
+
+// Kinds and status codes of the OTLP spans
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusOK         = 1
+	otlpStatusError      = 2
+)
+
+// traceFlagSampled is the flag of a W3C traceparent telling that the trace is recorded
+const traceFlagSampled = 0x01
+
+// TraceParent returns the W3C traceparent of the step being run, to be sent with the requests and the messages of an
+// executor so that the traces of the system under test join the trace of the run. It is empty when the run is not
+// traced.
+func TraceParent(ctx context.Context) string {
+	s, ok := ctx.Value(spanKey).(*span)
+	if !ok || s == nil {
+		return ""
+	}
+	return fmt.Sprintf("00-%s-%s-%02x", hex.EncodeToString(s.traceID[:]), hex.EncodeToString(s.spanID[:]), s.flags)
+}
+
+// ContextWithTraceParent returns a copy of ctx in which the steps join the trace of a W3C traceparent, as they do
+// during a traced run: this is the traceparent the executors run with ctx send, with TraceParent. ctx is returned
+// unchanged when traceparent is not valid.
+func ContextWithTraceParent(ctx context.Context, traceparent string) context.Context {
+	traceID, spanID, flags, ok := parseTraceParent(traceparent)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, spanKey, &span{traceID: traceID, spanID: spanID, flags: flags})
+}
+
+// InjectTraceParent sets the traceparent of the step being run on a request or a message of an executor, unless it
+// already has a traceparent: has tells if a header is set, whatever the case of its name, and set sets a header.
+// Nothing is set when the run is not traced.
+func InjectTraceParent(ctx context.Context, has func(key string) bool, set func(key, value string)) {
+	traceparent := TraceParent(ctx)
+	if traceparent == "" || has("traceparent") {
+		return
+	}
+	set("traceparent", traceparent)
+}
+
+// parseTraceParent returns the trace id, the parent id and the flags of a W3C traceparent
+func parseTraceParent(traceparent string) (traceID [16]byte, parentID [8]byte, flags byte, ok bool) {
+	parts := strings.Split(traceparent, "-")
+	if len(parts) != 4 || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return traceID, parentID, 0, false
+	}
+	t, err1 := hex.DecodeString(parts[1])
+	p, err2 := hex.DecodeString(parts[2])
+	f, err3 := hex.DecodeString(parts[3])
+	if err1 != nil || err2 != nil || err3 != nil {
+		return traceID, parentID, 0, false
+	}
+	copy(traceID[:], t)
+	copy(parentID[:], p)
+	return traceID, parentID, f[0], true
+}
+
+// tracer records the spans of a run: the run, the testsuites, the testcases and the steps, to be exported as
+// OpenTelemetry traces once the run is finished
+type tracer struct {
+	mu    sync.Mutex
+	spans []*span
+	// traceID, parentID and flags are read from the TRACEPARENT environment variable, so that a run joins the trace of
+	// a CI pipeline
+	traceID  [16]byte
+	parentID [8]byte
+	flags    byte
+}
+
+type span struct {
+	traceID    [16]byte
+	spanID     [8]byte
+	parentID   [8]byte
+	flags      byte
+	name       string
+	start, end time.Time
+	attributes []otlpKeyValue
+	events     []otlpEvent
+	status     otlpStatus
+}
+
+func newTracer() *tracer {
+	if traceID, parentID, flags, ok := parseTraceParent(os.Getenv("TRACEPARENT")); ok {
+		return &tracer{traceID: traceID, parentID: parentID, flags: flags}
+	}
+	t := &tracer{flags: traceFlagSampled}
+	rand.Read(t.traceID[:]) // nolint
+	return t
+}
+
+// startSpan starts a span, child of the span of ctx. It returns ctx unchanged and a nil span when the run is not
+// traced: the methods of a nil span do nothing.
+func (v *Venom) startSpan(ctx context.Context, name string, attributes ...otlpKeyValue) (context.Context, *span) {
+	if v.tracer == nil {
+		return ctx, nil
+	}
+	s := &span{traceID: v.tracer.traceID, parentID: v.tracer.parentID, flags: v.tracer.flags, name: name, start: time.Now(), attributes: attributes}
+	if parent, ok := ctx.Value(spanKey).(*span); ok && parent != nil {
+		s.parentID = parent.spanID
+	}
+	rand.Read(s.spanID[:]) // nolint
+	v.tracer.mu.Lock()
+	v.tracer.spans = append(v.tracer.spans, s)
+	v.tracer.mu.Unlock()
+	return context.WithValue(ctx, spanKey, s), s
+}
+
+// finish ends a span with the status of what it traced. A span is only ended once.
+func (s *span) finish(status Status, message string, attributes ...otlpKeyValue) {
+	if s == nil || !s.end.IsZero() {
+		return
+	}
+	s.end = time.Now()
+	s.attributes = append(s.attributes, attributes...)
+	switch status {
+	case StatusFail:
+		s.status = otlpStatus{Code: otlpStatusError, Message: message}
+	case StatusPass:
+		s.status = otlpStatus{Code: otlpStatusOK}
+	}
+}
+
+// startStepSpan starts the span of a step
+func (v *Venom) startStepSpan(ctx context.Context, tc *TestCase, tsResult *TestStepResult, e ExecutorRunner) (context.Context, *span) {
+	attributes := []otlpKeyValue{
+		otlpString("venom.testcase.name", tc.Name),
+		otlpString("venom.step.name", tsResult.Name),
+		otlpInt("venom.step.number", tsResult.Number),
+	}
+	if e != nil {
+		attributes = append(attributes, otlpString("venom.step.executor", e.Name()))
+	}
+	if tsResult.RangedEnable {
+		attributes = append(attributes, otlpInt("venom.step.range.index", tsResult.RangedIndex))
+	}
+	return v.startSpan(ctx, tsResult.Name, attributes...)
+}
+
+// endStepSpan ends the span of a step, with its retries, an event per assertion and an event per failure
+func endStepSpan(ctx context.Context, s *span, tsResult *TestStepResult) {
+	if s == nil {
+		return
+	}
+	var failed int
+	for _, a := range tsResult.AssertionsApplied.Assertions {
+		if !a.IsOK {
+			failed++
+		}
+		attributes := []otlpKeyValue{
+			otlpString("venom.assertion", HideSensitive(ctx, a.Assertion)),
+			otlpBool("venom.assertion.ok", a.IsOK),
+		}
+		if a.Severity != "" {
+			attributes = append(attributes, otlpString("venom.assertion.severity", a.Severity))
+		}
+		s.events = append(s.events, otlpEvent{TimeUnixNano: otlpTime(tsResult.End), Name: "assertion", Attributes: attributes})
+	}
+	var message string
+	for _, f := range tsResult.Errors {
+		value := HideSensitive(ctx, f.Value)
+		if message == "" {
+			message = value
+		}
+		s.events = append(s.events, otlpEvent{TimeUnixNano: otlpTime(tsResult.End), Name: "exception", Attributes: []otlpKeyValue{
+			otlpString("exception.type", f.Type),
+			otlpString("exception.message", value),
+		}})
+	}
+	s.finish(tsResult.Status, message,
+		otlpString("venom.step.status", string(tsResult.Status)),
+		otlpInt("venom.step.retries", stepRetries(*tsResult)),
+		otlpInt("venom.step.assertions", len(tsResult.AssertionsApplied.Assertions)),
+		otlpInt("venom.step.assertions.failed", failed),
+	)
+	// the span of an iteration of a ranged step may end once the aggregate assertions are evaluated
+	if !tsResult.End.IsZero() {
+		s.end = tsResult.End
+	}
+}
+
+// tracingReporter exports the spans of a run, to an OTLP/HTTP endpoint or to a file in the OTLP JSON format
+type tracingReporter struct {
+	BaseReporter
+	v *Venom
+}
+
+func (r *tracingReporter) RunEnd(ctx context.Context, tests *Tests) error {
+	data, err := json.Marshal(r.v.tracer.export())
+	if err != nil {
+		return errors.Wrap(err, "unable to marshal the traces")
+	}
+	if r.v.OTLPFile != "" {
+		if err := os.WriteFile(r.v.OTLPFile, append(data, '\n'), 0644); err != nil {
+			return errors.Wrapf(err, "unable to write the traces in %s", r.v.OTLPFile)
+		}
+		r.v.PrintFunc("Writing traces file %s\n", r.v.OTLPFile) // nolint
+	}
+	if r.v.OTLPEndpoint != "" {
+		if err := sendOTLP(ctx, r.v.OTLPEndpoint, data); err != nil {
+			return errors.Wrapf(err, "unable to send the traces to %s", r.v.OTLPEndpoint)
+		}
+	}
+	return nil
+}
+
+// sendOTLP posts traces to an OTLP/HTTP endpoint, with the headers of the OTEL_EXPORTER_OTLP_HEADERS environment
+// variable
+func sendOTLP(ctx context.Context, endpoint string, data []byte) error {
+	endpoint = strings.TrimSuffix(endpoint, "/")
+	if !strings.HasSuffix(endpoint, "/v1/traces") {
+		endpoint += "/v1/traces"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for _, header := range strings.Split(os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"), ",") {
+		if k, v, ok := strings.Cut(header, "="); ok {
+			req.Header.Set(strings.TrimSpace(k), strings.TrimSpace(v))
+		}
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// The types of the OTLP JSON format, https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              int            `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Events            []otlpEvent    `json:"events,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpEvent struct {
+	TimeUnixNano string         `json:"timeUnixNano"`
+	Name         string         `json:"name"`
+	Attributes   []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue *string         `json:"stringValue,omitempty"`
+	IntValue    *string         `json:"intValue,omitempty"`
+	BoolValue   *bool           `json:"boolValue,omitempty"`
+	ArrayValue  *otlpArrayValue `json:"arrayValue,omitempty"`
+}
+
+type otlpArrayValue struct {
+	Values []otlpValue `json:"values"`
+}
+
+func otlpString(key, value string) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpValue{StringValue: &value}}
+}
+
+func otlpInt(key string, value int) otlpKeyValue {
+	i := strconv.Itoa(value)
+	return otlpKeyValue{Key: key, Value: otlpValue{IntValue: &i}}
+}
+
+func otlpBool(key string, value bool) otlpKeyValue {
+	return otlpKeyValue{Key: key, Value: otlpValue{BoolValue: &value}}
+}
+
+func otlpStrings(key string, values []string) otlpKeyValue {
+	array := &otlpArrayValue{Values: []otlpValue{}}
+	for i := range values {
+		array.Values = append(array.Values, otlpValue{StringValue: &values[i]})
+	}
+	return otlpKeyValue{Key: key, Value: otlpValue{ArrayValue: array}}
+}
+
+// otlpTime returns a time in nanoseconds since the epoch, as a string as expected for 64 bits integers
+func otlpTime(t time.Time) string {
+	if t.IsZero() {
+		return "0"
+	}
+	return strconv.FormatInt(t.UnixNano(), 10)
+}
+
+// export returns the spans in the OTLP JSON format, with venom as service.name unless OTEL_SERVICE_NAME is set
+func (t *tracer) export() otlpTraces {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	serviceName := os.Getenv("OTEL_SERVICE_NAME")
+	if serviceName == "" {
+		serviceName = "venom"
+	}
+	hostname, _ := os.Hostname()
+	scopeSpans := otlpScopeSpans{Scope: otlpScope{Name: "github.com/ovh/venom", Version: Version}, Spans: []otlpSpan{}}
+	for _, s := range t.spans {
+		end := s.end
+		if end.IsZero() {
+			end = time.Now()
+		}
+		var parentID string
+		if s.parentID != [8]byte{} {
+			parentID = hex.EncodeToString(s.parentID[:])
+		}
+		scopeSpans.Spans = append(scopeSpans.Spans, otlpSpan{
+			TraceID:           hex.EncodeToString(s.traceID[:]),
+			SpanID:            hex.EncodeToString(s.spanID[:]),
+			ParentSpanID:      parentID,
+			Name:              s.name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: otlpTime(s.start),
+			EndTimeUnixNano:   otlpTime(end),
+			Attributes:        s.attributes,
+			Events:            s.events,
+			Status:            s.status,
+		})
+	}
+	return otlpTraces{ResourceSpans: []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: []otlpKeyValue{
+			otlpString("service.name", serviceName),
+			otlpString("service.version", Version),
+			otlpString("host.name", hostname),
+		}},
+		ScopeSpans: []otlpScopeSpans{scopeSpans},
+	}}}
+}
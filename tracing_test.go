@@ -0,0 +1,164 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// traceTestExecutor records the traceparent of the steps it runs
+type traceTestExecutor struct {
+	traceparents []string
+}
+
+func (e *traceTestExecutor) Run(ctx context.Context, step TestStep) (interface{}, error) {
+	e.traceparents = append(e.traceparents, TraceParent(ctx))
+	out, _ := step.StringValue("value")
+	return map[string]interface{}{"out": out}, nil
+}
+
+func TestTracing(t *testing.T) {
+	var received otlpTraces
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/traces", r.URL.Path)
+		authorization = r.Header.Get("Authorization")
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer server.Close()
+	t.Setenv("OTEL_EXPORTER_OTLP_HEADERS", "Authorization=Bearer token")
+	t.Setenv("TRACEPARENT", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "greet.yml"), []byte(reporterTestSuite), 0644))
+	e := &traceTestExecutor{}
+	_, err := Run(context.Background(), Options{
+		Paths:        []string{filepath.Join(dir, "greet.yml")},
+		Variables:    map[string]interface{}{"who": "world"},
+		Executors:    map[string]Executor{"echo": e},
+		OTLPEndpoint: server.URL,
+		OTLPFile:     filepath.Join(dir, "traces.json"),
+		LogOutput:    io.Discard,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer token", authorization)
+
+	data, err := os.ReadFile(filepath.Join(dir, "traces.json"))
+	require.NoError(t, err)
+	var traces otlpTraces
+	require.NoError(t, json.Unmarshal(data, &traces))
+	assert.Equal(t, received, traces)
+
+	require.Len(t, traces.ResourceSpans, 1)
+	assert.Equal(t, "venom", *traces.ResourceSpans[0].Resource.Attributes[0].Value.StringValue)
+	spans := traces.ResourceSpans[0].ScopeSpans[0].Spans
+	var names []string
+	byID := map[string]otlpSpan{}
+	for _, s := range spans {
+		names = append(names, s.Name)
+		byID[s.SpanID] = s
+		assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", s.TraceID)
+	}
+	assert.Equal(t, []string{"venom run", "greet", "hello", "echo", "goodbye", "echo"}, names)
+
+	parent := func(s otlpSpan) string { return byID[s.ParentSpanID].Name }
+	assert.Equal(t, "b7ad6b7169203331", spans[0].ParentSpanID)
+	assert.Equal(t, "venom run", parent(spans[1]))
+	assert.Equal(t, "greet", parent(spans[2]))
+	assert.Equal(t, "hello", parent(spans[3]))
+	assert.Equal(t, "goodbye", parent(spans[5]))
+
+	// the executors run with the traceparent of their step
+	require.Len(t, e.traceparents, 2)
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-"+spans[3].SpanID+"-01", e.traceparents[0])
+	assert.Equal(t, "00-0af7651916cd43dd8448eb211c80319c-"+spans[5].SpanID+"-01", e.traceparents[1])
+
+	attributes := func(s otlpSpan) map[string]string {
+		m := map[string]string{}
+		for _, a := range s.Attributes {
+			switch {
+			case a.Value.StringValue != nil:
+				m[a.Key] = *a.Value.StringValue
+			case a.Value.IntValue != nil:
+				m[a.Key] = *a.Value.IntValue
+			}
+		}
+		return m
+	}
+	assert.Equal(t, otlpStatus{Code: otlpStatusError}, spans[0].Status)
+	assert.Equal(t, "FAIL", attributes(spans[0])["venom.status"])
+	assert.Equal(t, otlpStatus{Code: otlpStatusOK}, spans[2].Status)
+	assert.Equal(t, "PASS", attributes(spans[2])["venom.testcase.status"])
+
+	step := spans[5]
+	assert.Equal(t, otlpStatusError, step.Status.Code)
+	assert.True(t, strings.Contains(step.Status.Message, `Assertion "out ShouldEqual \"hello world\""`), step.Status.Message)
+	assert.Equal(t, "echo", attributes(step)["venom.step.executor"])
+	assert.Equal(t, "FAIL", attributes(step)["venom.step.status"])
+	assert.Equal(t, "0", attributes(step)["venom.step.retries"])
+	assert.Equal(t, "1", attributes(step)["venom.step.assertions.failed"])
+	require.Len(t, step.Events, 2)
+	assert.Equal(t, "assertion", step.Events[0].Name)
+	assert.Equal(t, false, *step.Events[0].Attributes[1].Value.BoolValue)
+	assert.Equal(t, "exception", step.Events[1].Name)
+
+	assert.Equal(t, "", TraceParent(context.Background()))
+}
+
+func TestSpanFinish(t *testing.T) {
+	v := New()
+	v.tracer = newTracer()
+	_, s := v.startSpan(context.Background(), "venom run")
+	s.finish(StatusPass, "")
+	end := s.end
+	// the deferred finish of a run doesn't change a span already ended
+	s.finish(StatusFail, "unable to run")
+	assert.Equal(t, end, s.end)
+	assert.Equal(t, otlpStatus{Code: otlpStatusOK}, s.status)
+
+	var nilSpan *span
+	nilSpan.finish(StatusFail, "")
+}
+
+func TestTraceParentFlags(t *testing.T) {
+	// a trace which is not sampled stays not sampled
+	t.Setenv("TRACEPARENT", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-00")
+	v := New()
+	v.tracer = newTracer()
+	ctx, _ := v.startSpan(context.Background(), "venom run")
+	assert.True(t, strings.HasSuffix(TraceParent(ctx), "-00"), TraceParent(ctx))
+
+	t.Setenv("TRACEPARENT", "")
+	v.tracer = newTracer()
+	ctx, _ = v.startSpan(context.Background(), "venom run")
+	assert.True(t, strings.HasSuffix(TraceParent(ctx), "-01"), TraceParent(ctx))
+}
+
+func TestInjectTraceParent(t *testing.T) {
+	headers := map[string]string{}
+	has := func(key string) bool { _, ok := headers[key]; return ok }
+	set := func(key, value string) { headers[key] = value }
+
+	InjectTraceParent(context.Background(), has, set)
+	assert.Empty(t, headers)
+
+	ctx := ContextWithTraceParent(context.Background(), "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+	InjectTraceParent(ctx, has, set)
+	assert.Equal(t, map[string]string{"traceparent": "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"}, headers)
+
+	// the traceparent of the step is kept
+	headers = map[string]string{"traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"}
+	InjectTraceParent(ctx, has, set)
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", headers["traceparent"])
+
+	assert.Equal(t, context.Background(), ContextWithTraceParent(context.Background(), "00-invalid-01"))
+}
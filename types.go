@@ -11,6 +11,8 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/spf13/cast"
+
+	"github.com/ovh/venom/assertions"
 )
 
 type Status string
@@ -82,25 +84,39 @@ type Tests struct {
 	Duration         float64     `json:"duration" yaml:"-"`
 	Start            time.Time   `json:"start" yaml:"-"`
 	End              time.Time   `json:"end" yaml:"-"`
+	// Shard is the shard which ran the testsuites, like 3/8
+	Shard string `json:"shard,omitempty" yaml:"shard,omitempty"`
 }
 
 // TestSuite is a single JUnit test suite which may contain many
 // testcases.
 type TestSuiteXML struct {
-	XMLName   xml.Name      `xml:"testsuite" json:"-" yaml:"-"`
-	Disabled  int           `xml:"disabled,attr,omitempty" json:"disabled" yaml:""`
-	Errors    int           `xml:"errors,attr,omitempty" json:"errors" yaml:"-"`
-	Failures  int           `xml:"failures,attr,omitempty" json:"failures" yaml:"-"`
-	Hostname  string        `xml:"hostname,attr,omitempty" json:"hostname" yaml:"-"`
-	ID        string        `xml:"id,attr,omitempty" json:"id" yaml:"-"`
-	Name      string        `xml:"name,attr" json:"name" yaml:"name"`
-	Package   string        `xml:"package,attr,omitempty" json:"package" yaml:"-"`
-	Skipped   int           `xml:"skipped,attr,omitempty" json:"skipped" yaml:"skipped,omitempty"`
-	Total     int           `xml:"tests,attr" json:"total" yaml:"total,omitempty"`
-	TestCases []TestCaseXML `xml:"testcase" json:"testcases" yaml:"testcases"`
-	Version   string        `xml:"version,omitempty" json:"version" yaml:"version,omitempty"`
-	Time      string        `xml:"time,attr,omitempty" json:"time" yaml:"-"`
-	Timestamp string        `xml:"timestamp,attr,omitempty" json:"timestamp" yaml:"-"`
+	XMLName    xml.Name       `xml:"testsuite" json:"-" yaml:"-"`
+	Disabled   int            `xml:"disabled,attr,omitempty" json:"disabled" yaml:""`
+	Errors     int            `xml:"errors,attr,omitempty" json:"errors" yaml:"-"`
+	Failures   int            `xml:"failures,attr,omitempty" json:"failures" yaml:"-"`
+	Hostname   string         `xml:"hostname,attr,omitempty" json:"hostname" yaml:"-"`
+	ID         string         `xml:"id,attr,omitempty" json:"id" yaml:"-"`
+	Name       string         `xml:"name,attr" json:"name" yaml:"name"`
+	Package    string         `xml:"package,attr,omitempty" json:"package" yaml:"-"`
+	Skipped    int            `xml:"skipped,attr,omitempty" json:"skipped" yaml:"skipped,omitempty"`
+	Total      int            `xml:"tests,attr" json:"total" yaml:"total,omitempty"`
+	Properties *PropertiesXML `xml:"properties,omitempty" json:"properties,omitempty" yaml:"properties,omitempty"`
+	TestCases  []TestCaseXML  `xml:"testcase" json:"testcases" yaml:"testcases"`
+	Version    string         `xml:"version,omitempty" json:"version" yaml:"version,omitempty"`
+	Time       string         `xml:"time,attr,omitempty" json:"time" yaml:"-"`
+	Timestamp  string         `xml:"timestamp,attr,omitempty" json:"timestamp" yaml:"-"`
+}
+
+// PropertiesXML are the properties of a JUnit test suite
+type PropertiesXML struct {
+	Properties []PropertyXML `xml:"property" json:"properties" yaml:"properties"`
+}
+
+// PropertyXML is a property of a JUnit test suite
+type PropertyXML struct {
+	Name  string `xml:"name,attr" json:"name" yaml:"name"`
+	Value string `xml:"value,attr" json:"value" yaml:"value"`
 }
 
 type TestSuiteInput struct {
@@ -123,6 +139,10 @@ type TestSuite struct {
 	ComputedVars H      `json:"computed_vars" yaml:"-"`
 	WorkDir      string `json:"workdir" yaml:"_"`
 	Status       Status `json:"status" yaml:"status"`
+	// Shard is the shard which ran the testsuite, like 3/8
+	Shard string `json:"shard,omitempty" yaml:"-"`
+	// Hostname is the host which ran the testsuite
+	Hostname string `json:"hostname,omitempty" yaml:"-"`
 
 	Duration float64   `json:"duration" yaml:"-"`
 	Start    time.Time `json:"start" yaml:"-"`
@@ -135,16 +155,21 @@ type TestSuite struct {
 
 // TestCase is a single test case with its result.
 type TestCaseXML struct {
-	XMLName   xml.Name     `xml:"testcase" json:"-" yaml:"-"`
-	Classname string       `xml:"classname,attr,omitempty" json:"classname" yaml:"-"`
-	Errors    []FailureXML `xml:"error,omitempty" json:"errors" yaml:"errors,omitempty"`
-	Failures  []FailureXML `xml:"failure,omitempty" json:"failures" yaml:"failures,omitempty"`
-	Name      string       `xml:"name,attr" json:"name" yaml:"name"`
-	Skipped   []Skipped    `xml:"skipped,omitempty" json:"skipped" yaml:"skipped,omitempty"`
-	Systemout InnerResult  `xml:"system-out,omitempty" json:"systemout" yaml:"systemout,omitempty"`
-	Systemerr InnerResult  `xml:"system-err,omitempty" json:"systemerr" yaml:"systemerr,omitempty"`
-	Time      float64      `xml:"time,attr,omitempty" json:"time" yaml:"time,omitempty"`
-	ID        string       `xml:"id,attr,omitempty" json:"id" yaml:"id"`
+	XMLName   xml.Name `xml:"testcase" json:"-" yaml:"-"`
+	Classname string   `xml:"classname,attr,omitempty" json:"classname" yaml:"-"`
+	// Properties are the id, the tags and the variables of the testcase
+	Properties *PropertiesXML `xml:"properties,omitempty" json:"properties,omitempty" yaml:"properties,omitempty"`
+	Errors     []FailureXML   `xml:"error,omitempty" json:"errors" yaml:"errors,omitempty"`
+	Failures   []FailureXML   `xml:"failure,omitempty" json:"failures" yaml:"failures,omitempty"`
+	Name       string         `xml:"name,attr" json:"name" yaml:"name"`
+	Skipped    []Skipped      `xml:"skipped,omitempty" json:"skipped" yaml:"skipped,omitempty"`
+	Systemout  InnerResult    `xml:"system-out,omitempty" json:"systemout" yaml:"systemout,omitempty"`
+	Systemerr  InnerResult    `xml:"system-err,omitempty" json:"systemerr" yaml:"systemerr,omitempty"`
+	Time       float64        `xml:"time,attr,omitempty" json:"time" yaml:"time,omitempty"`
+	ID         string         `xml:"id,attr,omitempty" json:"id" yaml:"id"`
+	// File and Line locate the testcase, or the failed step, in the testsuite file
+	File string `xml:"file,attr,omitempty" json:"file,omitempty" yaml:"file,omitempty"`
+	Line int    `xml:"line,attr,omitempty" json:"line,omitempty" yaml:"line,omitempty"`
 }
 
 type TestCaseInput struct {
@@ -153,6 +178,7 @@ type TestCaseInput struct {
 	Skip         []string          `json:"skip" yaml:"skip"`
 	RawTestSteps []json.RawMessage `json:"steps" yaml:"steps"`
 	ID           string            `json:"id" yaml:"id"`
+	Tags         []string          `json:"tags,omitempty" yaml:"tags,omitempty"`
 }
 
 type TestCase struct {
@@ -166,10 +192,16 @@ type TestCase struct {
 	Duration float64   `json:"duration" yaml:"-"`
 	Start    time.Time `json:"start" yaml:"-"`
 	End      time.Time `json:"end" yaml:"-"`
+	// Line is the line of the testcase in the testsuite file
+	Line int `json:"line,omitempty" yaml:"-"`
+	// DeclaredVars are the vars of the testcase in the testsuite file, without the vars of the testsuite
+	DeclaredVars H `json:"declaredVars,omitempty" yaml:"-"`
 
 	testSteps       []TestStep       `json:"-" yaml:"-"`
 	TestStepResults []TestStepResult `json:"results" yaml:"-"`
 	TestSuiteVars   H                `json:"-" yaml:"-"`
+	// DryRunSteps are the steps rendered by a dry run
+	DryRunSteps []DryRunStep `json:"dryRunSteps,omitempty" yaml:"-"`
 
 	computedVars    H        `json:"-" yaml:"-"`
 	computedVerbose []string `json:"-" yaml:"-"`
@@ -180,6 +212,7 @@ type TestCase struct {
 type TestStepResult struct {
 	Name              string            `json:"name"`
 	Errors            []Failure         `json:"errors"`
+	Warnings          []Failure         `json:"warnings,omitempty" yaml:"warnings,omitempty"`
 	Skipped           []Skipped         `json:"skipped" yaml:"skipped"`
 	Status            Status            `json:"status" yaml:"status"`
 	Raw               interface{}       `json:"raw" yaml:"raw"`
@@ -192,6 +225,8 @@ type TestStepResult struct {
 	ComputedInfo      []string          `json:"computedInfos" yaml:"-"`
 	AssertionsApplied AssertionsApplied `json:"assertionsApplied" yaml:"-"`
 	Retries           int               `json:"retries" yaml:"retries"`
+	// DumpFile is the file of the variables, the step and the result of the executor, written with -vv
+	DumpFile string `json:"dumpFile,omitempty" yaml:"dumpFile,omitempty"`
 
 	Systemout string    `json:"systemout"`
 	Systemerr string    `json:"systemerr"`
@@ -201,7 +236,8 @@ type TestStepResult struct {
 }
 
 func (ts *TestStepResult) appendError(err error) {
-	ts.Errors = append(ts.Errors, Failure{Value: RemoveNotPrintableChar(err.Error())})
+	msg := RemoveNotPrintableChar(err.Error())
+	ts.Errors = append(ts.Errors, Failure{Type: FailureTypeError, Message: msg, Value: msg})
 	ts.Status = StatusFail
 }
 
@@ -263,17 +299,31 @@ type Skipped struct {
 	Value string `xml:",cdata" json:"value" yaml:"value,omitempty"`
 }
 
+// Types of failures
+const (
+	// FailureTypeAssertion is an assertion which is not verified
+	FailureTypeAssertion = "assertion"
+	// FailureTypeError is an error of an executor, or of a testsuite which can't be run as written
+	FailureTypeError = "error"
+)
+
 // Failure contains data related to a failed test.
 type Failure struct {
 	TestcaseClassname  string `xml:"-" json:"-" yaml:"-"`
 	TestcaseName       string `xml:"-" json:"-" yaml:"-"`
-	TestcaseLineNumber int    `xml:"-" json:"-" yaml:"-"`
+	TestcaseLineNumber int    `xml:"-" json:"line,omitempty" yaml:"line,omitempty"`
 	StepNumber         int    `xml:"-" json:"-" yaml:"-"`
 	Assertion          string `xml:"-" json:"-" yaml:"-"`
 	AssertionRequired  bool   `xml:"-" json:"-" yaml:"-"`
 	Error              error  `xml:"-" json:"-" yaml:"-"`
-
-	Value string `json:"value" yaml:"value,omitempty"`
+	Severity           string `xml:"-" json:"severity,omitempty" yaml:"severity,omitempty"`
+	// Type is FailureTypeAssertion or FailureTypeError
+	Type string `xml:"-" json:"type,omitempty" yaml:"type,omitempty"`
+	// Message is the error of the failure, without its location
+	Message string `xml:"-" json:"message,omitempty" yaml:"message,omitempty"`
+
+	Value string           `json:"value" yaml:"value,omitempty"`
+	Diff  *assertions.Diff `xml:"-" json:"diff,omitempty" yaml:"diff,omitempty"`
 }
 
 type FailureXML struct {
@@ -284,7 +334,8 @@ type FailureXML struct {
 
 func newFailure(ctx context.Context, tc TestCase, stepNumber int, rangedIndex int, assertion string, err error) *Failure {
 	filename := StringVarFromCtx(ctx, "venom.testsuite.filename")
-	var lineNumber = findLineNumber(filename, tc.originalName, stepNumber, assertion, -1)
+	// the testsuite is read from its path, the filename is relative to its directory
+	var lineNumber = findLineNumber(StringVarFromCtx(ctx, "venom.testsuite.filepath"), tc.originalName, stepNumber, assertion, -1)
 	var value string
 	if assertion != "" {
 		value = fmt.Sprintf(`Testcase %q, step #%d-%d: Assertion %q failed. %s (%v:%d)`,
@@ -314,7 +365,13 @@ func newFailure(ctx context.Context, tc TestCase, stepNumber int, rangedIndex in
 		StepNumber:         stepNumber,
 		Assertion:          assertion,
 		Error:              err,
+		Type:               FailureTypeError,
+		Message:            RemoveNotPrintableChar(err.Error()),
 		Value:              value,
+		Diff:               assertions.GetDiff(err),
+	}
+	if assertion != "" {
+		failure.Type = FailureTypeAssertion
 	}
 
 	return &failure
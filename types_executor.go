@@ -7,7 +7,6 @@ import (
 	"reflect"
 	"strings"
 
-	"github.com/gosimple/slug"
 	"github.com/ovh/cds/sdk/interpolate"
 	"github.com/pkg/errors"
 	"github.com/rockbears/yaml"
@@ -201,50 +200,13 @@ func (ux UserExecutor) ZeroValueResult() interface{} {
 }
 
 func (v *Venom) RunUserExecutor(ctx context.Context, runner ExecutorRunner, tcIn *TestCase, tsIn *TestStepResult, step TestStep) (interface{}, error) {
-	vrs := tcIn.TestSuiteVars.Clone()
-	uxIn := runner.GetExecutor().(UserExecutor)
-
-	for k, va := range uxIn.Input {
-		if strings.HasPrefix(k, "input.") {
-			// do not reinject input.vars from parent user executor if exists
-			continue
-		} else if !strings.HasPrefix(k, "venom") {
-			if vl, ok := step[k]; ok && vl != "" { // value from step
-				vrs.AddWithPrefix("input", k, vl)
-			} else { // default value from executor
-				vrs.AddWithPrefix("input", k, va)
-			}
-		} else {
-			vrs.Add(k, va)
-		}
-	}
-	// reload the user executor with the interpolated vars
-	_, exe, err := v.GetExecutorRunner(ctx, step, vrs)
+	tc, ux, err := v.userExecutorTestCase(ctx, runner, tcIn, step)
 	if err != nil {
-		return nil, errors.Wrapf(err, "unable to reload executor")
-	}
-	ux := exe.GetExecutor().(UserExecutor)
-
-	tc := &TestCase{
-		TestCaseInput: TestCaseInput{
-			Name:         ux.Executor,
-			RawTestSteps: ux.RawTestSteps,
-			Vars:         vrs,
-		},
-		TestSuiteVars:   tcIn.TestSuiteVars,
-		IsExecutor:      true,
-		TestStepResults: make([]TestStepResult, 0),
+		return nil, err
 	}
 
-	tc.originalName = tc.Name
-	tc.Name = slug.Make(tc.Name)
-	tc.Vars.Add("venom.testcase", tc.Name)
-	tc.Vars.Add("venom.executor.filename", ux.Filename)
-	tc.Vars.Add("venom.executor.name", ux.Executor)
-	tc.computedVars = H{}
-
 	Debug(ctx, "running user executor %v", tc.Name)
-	Debug(ctx, "with vars: %v", vrs)
+	Debug(ctx, "with vars: %v", tc.Vars)
 
 	v.runTestSteps(ctx, tc, tsIn)
 
@@ -331,3 +293,49 @@ func (v *Venom) RunUserExecutor(ctx context.Context, runner ExecutorRunner, tcIn
 	}
 	return result, nil
 }
+
+// userExecutorTestCase builds the testcase running the steps of a user executor, with the inputs given by the step
+func (v *Venom) userExecutorTestCase(ctx context.Context, runner ExecutorRunner, tcIn *TestCase, step TestStep) (*TestCase, UserExecutor, error) {
+	vrs := tcIn.TestSuiteVars.Clone()
+	uxIn := runner.GetExecutor().(UserExecutor)
+
+	for k, va := range uxIn.Input {
+		if strings.HasPrefix(k, "input.") {
+			// do not reinject input.vars from parent user executor if exists
+			continue
+		} else if !strings.HasPrefix(k, "venom") {
+			if vl, ok := step[k]; ok && vl != "" { // value from step
+				vrs.AddWithPrefix("input", k, vl)
+			} else { // default value from executor
+				vrs.AddWithPrefix("input", k, va)
+			}
+		} else {
+			vrs.Add(k, va)
+		}
+	}
+	// reload the user executor with the interpolated vars
+	_, exe, err := v.GetExecutorRunner(ctx, step, vrs)
+	if err != nil {
+		return nil, UserExecutor{}, errors.Wrapf(err, "unable to reload executor")
+	}
+	ux := exe.GetExecutor().(UserExecutor)
+
+	tc := &TestCase{
+		TestCaseInput: TestCaseInput{
+			Name:         ux.Executor,
+			RawTestSteps: ux.RawTestSteps,
+			Vars:         vrs,
+		},
+		TestSuiteVars:   tcIn.TestSuiteVars,
+		IsExecutor:      true,
+		TestStepResults: make([]TestStepResult, 0),
+	}
+
+	tc.originalName = tc.Name
+	tc.Name = slugify(tc.Name)
+	tc.Vars.Add("venom.testcase", tc.Name)
+	tc.Vars.Add("venom.executor.filename", ux.Filename)
+	tc.Vars.Add("venom.executor.name", ux.Executor)
+	tc.computedVars = H{}
+	return tc, ux, nil
+}
@@ -1,6 +1,15 @@
 package venom
 
-import "testing"
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
 
 func Test_RemoveNotPrintableChar(t *testing.T) {
 	type args struct {
@@ -26,3 +35,24 @@ func Test_RemoveNotPrintableChar(t *testing.T) {
 		})
 	}
 }
+
+func Test_newFailureLine(t *testing.T) {
+	// the testsuite is not in the current directory: its line is found from its path
+	dir := t.TempDir()
+	testsuite := `name: failure
+testcases:
+- name: hello
+  steps:
+  - type: exec
+    script: echo hello
+    assertions:
+    - result.systemout ShouldEqual bye
+`
+	file := filepath.Join(dir, "failure.yml")
+	require.NoError(t, os.WriteFile(file, []byte(testsuite), 0644))
+	ctx := context.WithValue(context.Background(), ContextKey("var.venom.testsuite.filename"), "failure.yml")
+	ctx = context.WithValue(ctx, ContextKey("var.venom.testsuite.filepath"), file)
+
+	failure := newFailure(ctx, TestCase{originalName: "hello"}, 0, 0, "result.systemout ShouldEqual bye", errors.New("expected: bye got: hello"))
+	assert.Contains(t, failure.Value, "(failure.yml:8)")
+}
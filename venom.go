@@ -18,6 +18,7 @@ import (
 	"github.com/ovh/cds/sdk/interpolate"
 	"github.com/pkg/errors"
 	"github.com/rockbears/yaml"
+	"github.com/sirupsen/logrus"
 	"github.com/spf13/cast"
 )
 
@@ -73,6 +74,41 @@ type Venom struct {
 	StopOnFailure bool
 	HtmlReport    bool
 	Verbose       int
+	// JUnitPerStep writes a JUnit testcase per step in the XML reports
+	JUnitPerStep bool
+	// HtmlHistory is the number of previous runs, read from the history directory of OutputDir, shown in the HTML
+	// report. The runs are added to the history directory when it is set.
+	HtmlHistory int
+	// OutputMode is OutputModePerSuite, the default, or OutputModeAggregate
+	OutputMode string
+	// SummaryFile, if set, is a file the markdown summary of the run is appended to
+	SummaryFile string
+	// OTLPEndpoint, if set, is an OTLP/HTTP endpoint the traces of the run are sent to
+	OTLPEndpoint string
+	// OTLPFile, if set, is a file the traces of the run are written to, in the OTLP JSON format
+	OTLPFile string
+	// MetricsFile, if set, is a file the Prometheus metrics of the run are written to, for the textfile collector of
+	// the node exporter
+	MetricsFile string
+	// Pushgateway, if set, is the URL of a Prometheus Pushgateway the metrics of the run are pushed to
+	Pushgateway string
+
+	// Debugger, if set, is called before and after each step
+	Debugger Debugger
+	// TestCaseWrapper, if set, is called to run each testcase which is not skipped: it must call run to run the
+	// testcase. A testcase which is not run is skipped.
+	TestCaseWrapper func(ctx context.Context, ts *TestSuite, tc *TestCase, run func(ctx context.Context))
+	// DryRun renders the steps without running them
+	DryRun bool
+	// Shard, if set, selects the testsuites to run
+	Shard *Shard
+
+	logger *logrus.Entry
+	// reporters are added with AddReporter, activeReporters are the reporters of the current run
+	reporters       []Reporter
+	activeReporters []Reporter
+	// tracer records the spans of the current run, when it is traced
+	tracer *tracer
 }
 
 var trace = color.New(color.Attribute(90)).SprintFunc()
@@ -99,6 +135,7 @@ func (v *Venom) AddVariables(variables map[string]interface{}) {
 	}
 }
 
+// AddSecrets adds values which are hidden in the logs and in the reports of all the testsuites
 func (v *Venom) AddSecrets(secrets map[string]interface{}) {
 	for k, s := range secrets {
 		v.secrets[k] = s
@@ -9,7 +9,9 @@ import (
 	"os"
 	"path"
 	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/fatih/color"
 	tap "github.com/mndrix/tap-go"
@@ -28,6 +30,14 @@ func init() {
 func (v *Venom) CleanUpSecrets(testSuite TestSuite) TestSuite {
 	for _, testCase := range testSuite.TestCases {
 		ctx := v.processSecrets(context.Background(), &testSuite, &testCase)
+		for k, v := range testCase.TestCaseInput.Vars {
+			if !strings.HasPrefix(k, "venom.") {
+				testCase.TestCaseInput.Vars[k] = HideSensitive(ctx, v)
+			}
+		}
+		for k, v := range testCase.DeclaredVars {
+			testCase.DeclaredVars[k] = HideSensitive(ctx, v)
+		}
 		for _, result := range testCase.TestStepResults {
 			for k, v := range result.ComputedVars {
 				if !strings.HasPrefix(k, "venom.") {
@@ -39,13 +49,8 @@ func (v *Venom) CleanUpSecrets(testSuite TestSuite) TestSuite {
 					result.InputVars[k] = HideSensitive(ctx, v)
 				}
 			}
-			for k, v := range testCase.TestCaseInput.Vars {
-				if !strings.HasPrefix(k, "venom.") {
-					testCase.TestCaseInput.Vars[k] = HideSensitive(ctx, v)
-				}
-			}
-			result.Raw = HideSensitive(ctx, fmt.Sprint(result.Raw))
-			result.Interpolated = HideSensitive(ctx, fmt.Sprint(result.Interpolated))
+			result.Raw = HideSensitive(ctx, stepContent(result.Raw))
+			result.Interpolated = HideSensitive(ctx, stepContent(result.Interpolated))
 			result.Systemout = HideSensitive(ctx, result.Systemout)
 			result.Systemerr = HideSensitive(ctx, result.Systemerr)
 		}
@@ -53,11 +58,105 @@ func (v *Venom) CleanUpSecrets(testSuite TestSuite) TestSuite {
 	return testSuite
 }
 
+// unixMilli returns a time in milliseconds since the epoch, 0 for the zero time
+func unixMilli(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixMilli()
+}
+
+// stepRetries returns the number of times a step was run again: Retries counts the attempts of a step which failed
+func stepRetries(res TestStepResult) int {
+	if res.Status == StatusFail && res.Retries > 0 {
+		return res.Retries - 1
+	}
+	return res.Retries
+}
+
+// stepContent returns the content of a step, which is the YAML of the step before the results are cleaned up
+func stepContent(content interface{}) string {
+	if b, ok := content.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(content)
+}
+
+const (
+	// OutputModePerSuite writes a file per testsuite and per format in the output directory
+	OutputModePerSuite = "per-suite"
+	// OutputModeAggregate writes a single file per format, with all the testsuites, in the output directory
+	OutputModeAggregate = "aggregate"
+)
+
+// outputFormats are the formats of the reports written in the output directory
+var outputFormats = []string{"json", "tap", "xml", "yml", "yaml", "html", "ctrf", "allure", "markdown"}
+
+// ParseFormats parses a comma separated list of formats, such as xml,json,tap
+func ParseFormats(formats string) ([]string, error) {
+	var res []string
+	for _, f := range splitFormats(formats) {
+		var known bool
+		for _, o := range outputFormats {
+			known = known || f == o
+		}
+		if !known {
+			return nil, fmt.Errorf("invalid format %q: must be %s or %s", f, strings.Join(outputFormats[:len(outputFormats)-1], ", "), outputFormats[len(outputFormats)-1])
+		}
+		res = append(res, f)
+	}
+	return res, nil
+}
+
+// splitFormats splits a comma separated list of formats, without the blank and duplicated ones
+func splitFormats(formats string) []string {
+	var res []string
+	for _, f := range strings.Split(formats, ",") {
+		f = strings.TrimSpace(f)
+		var duplicated bool
+		for _, r := range res {
+			duplicated = duplicated || f == r
+		}
+		if f != "" && !duplicated {
+			res = append(res, f)
+		}
+	}
+	return res
+}
+
 // OutputResult output result to sdtout, files...
 func (v *Venom) OutputResult() error {
 	if v.OutputDir == "" {
 		return nil
 	}
+	for _, r := range v.outputReporters() {
+		if err := r.RunEnd(context.Background(), &v.Tests); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// outputReporters returns the reporters writing the results in the output directory: a reporter per format of the
+// comma separated OutputFormat, and the HTML report
+func (v *Venom) outputReporters() []Reporter {
+	var reporters []Reporter
+	htmlReport := v.HtmlReport
+	for _, format := range splitFormats(v.OutputFormat) {
+		if format == "html" {
+			htmlReport = true
+			continue
+		}
+		reporters = append(reporters, &formatReporter{v: v, format: format})
+	}
+	if htmlReport {
+		reporters = append(reporters, &htmlReporter{v: v})
+	}
+	return reporters
+}
+
+// cleanedTestSuites returns the evaluated testcases of the testsuites, with the secrets hidden
+func (v *Venom) cleanedTestSuites() []TestSuite {
 	cleanedTs := []TestSuite{}
 	for i := range v.Tests.TestSuites {
 		tcFiltered := []TestCase{}
@@ -67,81 +166,209 @@ func (v *Venom) OutputResult() error {
 			}
 		}
 		v.Tests.TestSuites[i].TestCases = tcFiltered
-		ts := v.CleanUpSecrets(v.Tests.TestSuites[i])
-		cleanedTs = append(cleanedTs, ts)
-
-		testsResult := &Tests{
-			TestSuites:       []TestSuite{ts},
-			Status:           v.Tests.Status,
-			NbTestsuitesFail: v.Tests.NbTestsuitesFail,
-			NbTestsuitesPass: v.Tests.NbTestsuitesPass,
-			NbTestsuitesSkip: v.Tests.NbTestsuitesSkip,
-			Duration:         v.Tests.Duration,
-			Start:            v.Tests.Start,
-			End:              v.Tests.End,
-		}
-
-		var data []byte
-		var err error
-
-		switch v.OutputFormat {
-		case "json":
-			data, err = json.MarshalIndent(testsResult, "", "  ")
-			if err != nil {
-				return errors.Wrapf(err, "Error: cannot format output json (%s)", err)
-			}
-		case "tap":
-			data, err = outputTapFormat(*testsResult)
-			if err != nil {
-				return errors.Wrapf(err, "Error: cannot format output tap (%s)", err)
-			}
-		case "yml", "yaml":
-			data, err = yaml.Marshal(testsResult)
-			if err != nil {
-				return errors.Wrapf(err, "Error: cannot format output yaml (%s)", err)
-			}
-		case "xml":
-			data, err = outputXMLFormat(*testsResult)
-			if err != nil {
-				return errors.Wrapf(err, "Error: cannot format output xml (%s)", err)
-			}
-		case "html":
-			return errors.New("Error: you have to use the --html-report flag")
+		cleanedTs = append(cleanedTs, v.CleanUpSecrets(v.Tests.TestSuites[i]))
+	}
+	return cleanedTs
+}
+
+// withTestSuites returns the results of the run with only some testsuites
+func (v *Venom) withTestSuites(testSuites []TestSuite) *Tests {
+	return &Tests{
+		TestSuites:       testSuites,
+		Status:           v.Tests.Status,
+		NbTestsuitesFail: v.Tests.NbTestsuitesFail,
+		NbTestsuitesPass: v.Tests.NbTestsuitesPass,
+		NbTestsuitesSkip: v.Tests.NbTestsuitesSkip,
+		Duration:         v.Tests.Duration,
+		Start:            v.Tests.Start,
+		End:              v.Tests.End,
+		Shard:            v.Tests.Shard,
+	}
+}
+
+// formatReporter writes a file per testsuite in the output directory, or a single file with OutputModeAggregate, in a
+// format: json, tap, xml, yaml, ctrf or markdown, or the allure-results directory
+type formatReporter struct {
+	BaseReporter
+	v      *Venom
+	format string
+}
+
+func (r *formatReporter) RunEnd(ctx context.Context, tests *Tests) error {
+	v := r.v
+	if r.format == "allure" {
+		dir := filepath.Join(v.OutputDir, AllureResultsDir)
+		if err := WriteAllureResults(dir, v.withTestSuites(v.cleanedTestSuites())); err != nil {
+			return err
+		}
+		v.PrintFunc("Writing allure results in %s\n", dir)
+		return nil
+	}
+	if v.OutputMode == OutputModeAggregate {
+		data, err := FormatTestsWithOptions(v.withTestSuites(v.cleanedTestSuites()), r.format, FormatOptions{JUnitPerStep: v.JUnitPerStep})
+		if err != nil {
+			return err
+		}
+		filename := path.Join(v.OutputDir, "test_results."+FormatExtension(r.format))
+		if err := os.WriteFile(filename, data, 0600); err != nil {
+			return fmt.Errorf("Error while creating file %s: %v", filename, err)
+		}
+		v.PrintFunc("Writing file %s\n", filename)
+		return nil
+	}
+	for _, ts := range v.cleanedTestSuites() {
+		data, err := FormatTestsWithOptions(v.withTestSuites([]TestSuite{ts}), r.format, FormatOptions{JUnitPerStep: v.JUnitPerStep})
+		if err != nil {
+			return err
 		}
 
 		fname := strings.TrimSuffix(ts.Filepath, filepath.Ext(ts.Filepath))
 		fname = strings.ReplaceAll(fname, "/", "_")
-		filename := path.Join(v.OutputDir, "test_results_"+fname+"."+v.OutputFormat)
+		filename := path.Join(v.OutputDir, "test_results_"+fname+"."+FormatExtension(r.format))
 		if err := os.WriteFile(filename, data, 0600); err != nil {
 			return fmt.Errorf("Error while creating file %s: %v", filename, err)
 		}
 		v.PrintFunc("Writing file %s\n", filename)
 	}
+	return nil
+}
 
-	if v.HtmlReport {
-		testsResult := &Tests{
-			TestSuites:       cleanedTs,
-			Status:           v.Tests.Status,
-			NbTestsuitesFail: v.Tests.NbTestsuitesFail,
-			NbTestsuitesPass: v.Tests.NbTestsuitesPass,
-			NbTestsuitesSkip: v.Tests.NbTestsuitesSkip,
-			Duration:         v.Tests.Duration,
-			Start:            v.Tests.Start,
-			End:              v.Tests.End,
+// htmlReporter writes the HTML report of all the testsuites in the output directory. With HtmlHistory, the report
+// shows the previous runs of the history directory, and the run is added to it.
+type htmlReporter struct {
+	BaseReporter
+	v *Venom
+}
+
+func (r *htmlReporter) RunEnd(ctx context.Context, tests *Tests) error {
+	v := r.v
+	current := v.withTestSuites(v.cleanedTestSuites())
+	historyDir := filepath.Join(v.OutputDir, HTMLHistoryDir)
+	var history []Tests
+	if v.HtmlHistory > 0 {
+		var errs []error
+		history, errs = readHTMLHistory(historyDir, v.HtmlHistory)
+		for _, err := range errs {
+			Warn(ctx, "report ignored in the history: %v", err)
 		}
+	}
+	data, err := outputHTML(current, history...)
+	if err != nil {
+		return errors.Wrapf(err, "Error: cannot format output html")
+	}
+	var filename = filepath.Join(v.OutputDir, computeOutputFilename("test_results.html"))
+	v.PrintFunc("Writing html file %s\n", filename)
+	if err := os.WriteFile(filename, data, 0600); err != nil {
+		return errors.Wrapf(err, "Error while creating file %s", filename)
+	}
+	if v.HtmlHistory == 0 {
+		return nil
+	}
+
+	// the report of the run is kept for the history of the next runs
+	data, err = json.Marshal(current)
+	if err != nil {
+		return errors.Wrapf(err, "Error: cannot format output json")
+	}
+	if err := os.MkdirAll(historyDir, os.FileMode(0755)); err != nil {
+		return errors.Wrapf(err, "unable to create directory %s", historyDir)
+	}
+	filename = filepath.Join(historyDir, "test_results_"+v.Tests.Start.Format("20060102T150405")+".json")
+	if err := os.WriteFile(filename, data, 0600); err != nil {
+		return errors.Wrapf(err, "Error while creating file %s", filename)
+	}
+	return nil
+}
+
+// summaryReporter appends the markdown summary of all the testsuites to a file
+type summaryReporter struct {
+	BaseReporter
+	v        *Venom
+	filename string
+}
+
+func (r *summaryReporter) RunEnd(ctx context.Context, tests *Tests) error {
+	v := r.v
+	data, err := outputMarkdownFormat(*v.withTestSuites(v.cleanedTestSuites()))
+	if err != nil {
+		return err
+	}
+	// the summary is appended, as the $GITHUB_STEP_SUMMARY file of the step can already have a content
+	f, err := os.OpenFile(r.filename, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return errors.Wrapf(err, "Error while opening file %s", r.filename)
+	}
+	defer f.Close() // nolint
+	if _, err := f.Write(data); err != nil {
+		return errors.Wrapf(err, "Error while writing file %s", r.filename)
+	}
+	v.PrintFunc("Writing summary file %s\n", r.filename)
+	return nil
+}
+
+// FormatOptions are the options of the formats of tests results
+type FormatOptions struct {
+	// JUnitPerStep writes a JUnit testcase per step, instead of per testcase
+	JUnitPerStep bool
+}
+
+// FormatExtension returns the extension of the files of a format
+func FormatExtension(format string) string {
+	switch format {
+	case "ctrf":
+		return "ctrf.json"
+	case "markdown":
+		return "md"
+	}
+	return format
+}
 
-		data, err := outputHTML(testsResult)
+// FormatTests renders tests results in a format: json, tap, xml, yaml, html, ctrf or markdown
+func FormatTests(tests *Tests, format string) ([]byte, error) {
+	return FormatTestsWithOptions(tests, format, FormatOptions{})
+}
+
+// FormatTestsWithOptions renders tests results in a format, with some options
+func FormatTestsWithOptions(tests *Tests, format string, opts FormatOptions) ([]byte, error) {
+	switch format {
+	case "json":
+		data, err := json.MarshalIndent(tests, "", "  ")
 		if err != nil {
-			return errors.Wrapf(err, "Error: cannot format output html")
+			return nil, errors.Wrapf(err, "Error: cannot format output json (%s)", err)
 		}
-		var filename = filepath.Join(v.OutputDir, computeOutputFilename("test_results.html"))
-		v.PrintFunc("Writing html file %s\n", filename)
-		if err := os.WriteFile(filename, data, 0600); err != nil {
-			return errors.Wrapf(err, "Error while creating file %s", filename)
+		return data, nil
+	case "tap":
+		data, err := outputTapFormat(*tests)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error: cannot format output tap (%s)", err)
+		}
+		return data, nil
+	case "yml", "yaml":
+		data, err := yaml.Marshal(tests)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error: cannot format output yaml (%s)", err)
+		}
+		return data, nil
+	case "xml":
+		data, err := outputXMLFormat(*tests, opts)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error: cannot format output xml (%s)", err)
 		}
+		return data, nil
+	case "html":
+		data, err := outputHTML(tests)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Error: cannot format output html")
+		}
+		return data, nil
+	case "ctrf":
+		return outputCTRFFormat(*tests)
+	case "markdown", "md":
+		return outputMarkdownFormat(*tests)
+	case "allure":
+		return nil, errors.New("Error: allure results are a directory, write them with WriteAllureResults")
 	}
-
-	return nil
+	return nil, fmt.Errorf("Error: unsupported format %q", format)
 }
 
 func outputTapFormat(tests Tests) ([]byte, error) {
@@ -159,10 +386,13 @@ func outputTapFormat(tests Tests) ([]byte, error) {
 			}
 
 			for _, testStepResult := range tc.TestStepResults {
+				for _, w := range testStepResult.Warnings {
+					tapValue.Diagnosticf("Warning: %s", w.Value)
+				}
 				if len(testStepResult.Errors) > 0 {
 					tapValue.Fail(name)
 					for _, e := range testStepResult.Errors {
-						tapValue.Diagnosticf("Error: %s", e.Value)
+						tapValue.Diagnosticf("Error: %s", failureWithDiff(e))
 					}
 					continue
 				}
@@ -171,62 +401,154 @@ func outputTapFormat(tests Tests) ([]byte, error) {
 		}
 	}
 	tapValue.Header(total)
+	if tests.Shard != "" {
+		tapValue.Diagnosticf("Shard: %s", tests.Shard)
+	}
 
 	return buf.Bytes(), nil
 }
 
-func outputXMLFormat(tests Tests) ([]byte, error) {
+func outputXMLFormat(tests Tests, opts FormatOptions) ([]byte, error) {
 	testsXML := TestsXML{}
 
 	for _, ts := range tests.TestSuites {
 		tsXML := TestSuiteXML{
-			Name:    ts.Name,
-			Package: ts.Filepath,
-			Time:    fmt.Sprintf("%f", ts.Duration),
+			Name:     ts.Name,
+			Package:  ts.Filepath,
+			Hostname: ts.Hostname,
+			Time:     fmt.Sprintf("%f", ts.Duration),
+		}
+		if !ts.Start.IsZero() {
+			tsXML.Timestamp = ts.Start.Format("2006-01-02T15:04:05")
+		}
+		if ts.Shard != "" {
+			tsXML.Properties = &PropertiesXML{Properties: []PropertyXML{{Name: "shard", Value: ts.Shard}}}
 		}
 
 		for _, tc := range ts.TestCases {
-			switch tc.Status {
-			case StatusFail:
-				tsXML.Errors++
-			case StatusSkip:
-				tsXML.Skipped++
-			}
-			tsXML.Total++
-
-			failuresXML := []FailureXML{}
-			systemout := InnerResult{}
-			systemerr := InnerResult{}
-			for _, result := range tc.TestStepResults {
-				for _, failure := range result.Errors {
-					failuresXML = append(failuresXML, FailureXML{
-						Value: failure.Value,
-					})
+			var tcsXML []TestCaseXML
+			if opts.JUnitPerStep && len(tc.TestStepResults) > 0 {
+				for _, result := range tc.TestStepResults {
+					tcXML := newTestCaseXML(ts, tc, []TestStepResult{result})
+					tcXML.Name = fmt.Sprintf("%s / #%d %s", tc.Name, result.Number, result.Name)
+					tcXML.Time = result.Duration
+					if result.Status == StatusSkip {
+						tcXML.Skipped = append(tcXML.Skipped, result.Skipped...)
+						if len(tcXML.Skipped) == 0 {
+							tcXML.Skipped = []Skipped{{Value: "step skipped"}}
+						}
+					}
+					tcsXML = append(tcsXML, tcXML)
 				}
-				systemout.Value += result.Systemout
-				systemerr.Value += result.Systemerr
+			} else {
+				tcXML := newTestCaseXML(ts, tc, tc.TestStepResults)
+				tcXML.Skipped = tc.Skipped
+				tcsXML = append(tcsXML, tcXML)
 			}
 
-			tcXML := TestCaseXML{
-				Classname: ts.Filename,
-				Errors:    failuresXML,
-				Name:      tc.Name,
-				Skipped:   tc.Skipped,
-				Systemout: systemout,
-				Systemerr: systemerr,
-				Time:      tc.Duration,
-				ID:        tc.ID,
+			for _, tcXML := range tcsXML {
+				switch {
+				case len(tcXML.Errors) > 0:
+					tsXML.Errors++
+				case len(tcXML.Failures) > 0:
+					tsXML.Failures++
+				case len(tcXML.Skipped) > 0:
+					tsXML.Skipped++
+				}
+				tsXML.Total++
 			}
-			tsXML.TestCases = append(tsXML.TestCases, tcXML)
+			tsXML.TestCases = append(tsXML.TestCases, tcsXML...)
 		}
 		testsXML.TestSuites = append(testsXML.TestSuites, tsXML)
 	}
 
 	dataxml, err := xml.MarshalIndent(testsXML, "", "  ")
 	if err != nil {
-		errors.Wrapf(err, "Error: cannot format xml output")
+		return nil, errors.Wrapf(err, "Error: cannot format xml output")
 	}
 	data := append([]byte(`<?xml version="1.0" encoding="utf-8"?>`), dataxml...)
 
 	return data, nil
 }
+
+// newTestCaseXML returns the JUnit testcase of the results of some steps of a testcase. A testcase with an error of
+// an executor is in error, a testcase with only assertions which are not verified is failed.
+func newTestCaseXML(ts TestSuite, tc TestCase, results []TestStepResult) TestCaseXML {
+	tcXML := TestCaseXML{
+		Classname:  ts.Filename,
+		Name:       tc.Name,
+		Time:       tc.Duration,
+		ID:         tc.ID,
+		File:       ts.Filepath,
+		Line:       tc.Line,
+		Properties: testCaseProperties(ts, tc),
+	}
+
+	withSteps := len(results) > 1
+	var failureLine bool
+	for _, result := range results {
+		for _, failure := range result.Errors {
+			failureXML := FailureXML{
+				Value:   failureWithDiff(failure),
+				Type:    failure.Type,
+				Message: failure.Message,
+			}
+			if failureXML.Message == "" {
+				failureXML.Message = failure.Value
+			}
+			if failure.Type == FailureTypeAssertion {
+				tcXML.Failures = append(tcXML.Failures, failureXML)
+			} else {
+				// failures of reports written before the type of failures are errors, as they used to be
+				tcXML.Errors = append(tcXML.Errors, failureXML)
+			}
+			if !failureLine && failure.TestcaseLineNumber > 0 {
+				// links to the first failure rather than to the testcase
+				tcXML.Line = failure.TestcaseLineNumber
+				failureLine = true
+			}
+		}
+
+		if withSteps {
+			// the outputs of the steps are separated by their names
+			header := fmt.Sprintf("--- step #%d %s\n", result.Number, result.Name)
+			if len(result.Warnings) > 0 || strings.TrimSpace(result.Systemout) != "" {
+				tcXML.Systemout.Value += header
+			}
+			if strings.TrimSpace(result.Systemerr) != "" {
+				tcXML.Systemerr.Value += header
+			}
+		}
+		for _, warning := range result.Warnings {
+			tcXML.Systemout.Value += "WARNING: " + warning.Value + "\n"
+		}
+		tcXML.Systemout.Value += result.Systemout
+		tcXML.Systemerr.Value += result.Systemerr
+	}
+	return tcXML
+}
+
+// testCaseProperties returns the id, the tags and the vars declared by a testcase, as JUnit properties. The secrets
+// of its testsuite are hidden.
+func testCaseProperties(ts TestSuite, tc TestCase) *PropertiesXML {
+	var properties []PropertyXML
+	if tc.ID != "" {
+		properties = append(properties, PropertyXML{Name: "id", Value: tc.ID})
+	}
+	for _, tag := range tc.Tags {
+		properties = append(properties, PropertyXML{Name: "tag", Value: tag})
+	}
+	ctx := context.WithValue(context.Background(), ContextKey("secrets"), testCaseSecrets(&ts, &tc))
+	keys := make([]string, 0, len(tc.DeclaredVars))
+	for k := range tc.DeclaredVars {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		properties = append(properties, PropertyXML{Name: "var." + k, Value: HideSensitive(ctx, tc.DeclaredVars[k])})
+	}
+	if len(properties) == 0 {
+		return nil
+	}
+	return &PropertiesXML{Properties: properties}
+}
@@ -0,0 +1,269 @@
+package venom
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// AllureResultsDir is the directory of the Allure results, in the output directory
+const AllureResultsDir = "allure-results"
+
+type allureResult struct {
+	UUID          string               `json:"uuid"`
+	HistoryID     string               `json:"historyId"`
+	TestCaseID    string               `json:"testCaseId,omitempty"`
+	Name          string               `json:"name"`
+	FullName      string               `json:"fullName"`
+	Status        string               `json:"status"`
+	StatusDetails *allureStatusDetails `json:"statusDetails,omitempty"`
+	Stage         string               `json:"stage"`
+	Start         int64                `json:"start,omitempty"`
+	Stop          int64                `json:"stop,omitempty"`
+	Labels        []allureLabel        `json:"labels"`
+	Steps         []allureStep         `json:"steps,omitempty"`
+}
+
+type allureStep struct {
+	Name          string               `json:"name"`
+	Status        string               `json:"status"`
+	StatusDetails *allureStatusDetails `json:"statusDetails,omitempty"`
+	Stage         string               `json:"stage"`
+	Start         int64                `json:"start,omitempty"`
+	Stop          int64                `json:"stop,omitempty"`
+	Parameters    []allureParameter    `json:"parameters,omitempty"`
+	Steps         []allureStep         `json:"steps,omitempty"`
+	Attachments   []allureAttachment   `json:"attachments,omitempty"`
+}
+
+type allureStatusDetails struct {
+	Message string `json:"message,omitempty"`
+	Trace   string `json:"trace,omitempty"`
+}
+
+type allureLabel struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type allureParameter struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type allureAttachment struct {
+	Name   string `json:"name"`
+	Source string `json:"source"`
+	Type   string `json:"type"`
+}
+
+// allureWriter writes the files of the Allure results in a directory
+type allureWriter struct {
+	dir string
+}
+
+// WriteAllureResults writes the results of tests in dir, in the Allure results format: a result file per testcase,
+// with the steps and their attachments
+func WriteAllureResults(dir string, tests *Tests) error {
+	if err := os.MkdirAll(dir, os.FileMode(0755)); err != nil {
+		return errors.Wrapf(err, "unable to create directory %s", dir)
+	}
+	w := &allureWriter{dir: dir}
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			result, err := w.testCaseResult(ts, tc)
+			if err != nil {
+				return err
+			}
+			if err := w.writeJSON(result.UUID+"-result.json", result); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *allureWriter) testCaseResult(ts TestSuite, tc TestCase) (*allureResult, error) {
+	result := &allureResult{
+		UUID:       newUUID(),
+		HistoryID:  fmt.Sprintf("%x", sha256.Sum256([]byte(ts.Filepath+"/"+tc.Name))),
+		TestCaseID: tc.ID,
+		Name:       tc.Name,
+		FullName:   ts.Name + " / " + tc.Name,
+		Stage:      "finished",
+		Start:      unixMilli(tc.Start),
+		Stop:       unixMilli(tc.End),
+		Labels: []allureLabel{
+			{Name: "suite", Value: ts.Name},
+			{Name: "package", Value: ts.Filepath},
+			{Name: "framework", Value: "venom"},
+		},
+	}
+	if ts.Hostname != "" {
+		result.Labels = append(result.Labels, allureLabel{Name: "host", Value: ts.Hostname})
+	}
+	for _, tag := range tc.Tags {
+		result.Labels = append(result.Labels, allureLabel{Name: "tag", Value: tag})
+	}
+
+	var failures []Failure
+	for i := 0; i < len(tc.TestStepResults); {
+		res := tc.TestStepResults[i]
+		if !res.RangedEnable {
+			step, err := w.step(res)
+			if err != nil {
+				return nil, err
+			}
+			result.Steps = append(result.Steps, step)
+			failures = append(failures, res.Errors...)
+			i++
+			continue
+		}
+
+		// the iterations of a ranged step, and its aggregate assertions, are nested in a step
+		ranged := allureStep{Name: strings.Split(res.Name, " (range=")[0], Stage: "finished", Start: unixMilli(res.Start)}
+		var rangedFailures []Failure
+		for ; i < len(tc.TestStepResults) && tc.TestStepResults[i].RangedEnable && tc.TestStepResults[i].Number == res.Number; i++ {
+			step, err := w.step(tc.TestStepResults[i])
+			if err != nil {
+				return nil, err
+			}
+			ranged.Steps = append(ranged.Steps, step)
+			ranged.Stop = unixMilli(tc.TestStepResults[i].End)
+			rangedFailures = append(rangedFailures, tc.TestStepResults[i].Errors...)
+		}
+		ranged.Status, ranged.StatusDetails = allureStatus(StatusPass, rangedFailures, nil)
+		result.Steps = append(result.Steps, ranged)
+		failures = append(failures, rangedFailures...)
+	}
+	result.Status, result.StatusDetails = allureStatus(tc.Status, failures, tc.Skipped)
+	return result, nil
+}
+
+func (w *allureWriter) step(res TestStepResult) (allureStep, error) {
+	step := allureStep{
+		Name:  res.Name,
+		Stage: "finished",
+		Start: unixMilli(res.Start),
+		Stop:  unixMilli(res.End),
+	}
+	step.Status, step.StatusDetails = allureStatus(res.Status, res.Errors, res.Skipped)
+	if retries := stepRetries(res); retries > 0 {
+		step.Parameters = append(step.Parameters, allureParameter{Name: "retries", Value: strconv.Itoa(retries)})
+	}
+
+	// the step as it was run, the variables computed from the result of its executor, and the dump of the step
+	// written with -vv
+	if content := strings.TrimSpace(stepContent(res.Interpolated)); content != "" && content != "<nil>" {
+		if err := w.attach(&step, "step", "text/yaml", "yaml", []byte(content+"\n")); err != nil {
+			return step, err
+		}
+	}
+	variables := H{}
+	for k, v := range res.ComputedVars {
+		if !strings.HasPrefix(k, "venom.") && !strings.HasPrefix(k, "__") {
+			variables[k] = v
+		}
+	}
+	if len(variables) > 0 {
+		data, err := json.MarshalIndent(variables, "", "  ")
+		if err != nil {
+			return step, errors.Wrapf(err, "unable to marshal the variables of step %q", res.Name)
+		}
+		if err := w.attach(&step, "variables", "application/json", "json", data); err != nil {
+			return step, err
+		}
+	}
+	if res.DumpFile != "" {
+		// the dump files are not kept with the reports: they are attached when they still exist
+		if data, err := os.ReadFile(res.DumpFile); err == nil {
+			if err := w.attach(&step, "dump", "application/json", "json", data); err != nil {
+				return step, err
+			}
+		}
+	}
+	if len(res.ComputedInfo) > 0 {
+		if err := w.attach(&step, "info", "text/plain", "txt", []byte(strings.Join(res.ComputedInfo, "\n")+"\n")); err != nil {
+			return step, err
+		}
+	}
+	if strings.TrimSpace(res.Systemout) != "" {
+		if err := w.attach(&step, "systemout", "text/plain", "txt", []byte(res.Systemout)); err != nil {
+			return step, err
+		}
+	}
+	if strings.TrimSpace(res.Systemerr) != "" {
+		if err := w.attach(&step, "systemerr", "text/plain", "txt", []byte(res.Systemerr)); err != nil {
+			return step, err
+		}
+	}
+	return step, nil
+}
+
+func (w *allureWriter) attach(step *allureStep, name, mimeType, ext string, data []byte) error {
+	source := newUUID() + "-attachment." + ext
+	if err := os.WriteFile(filepath.Join(w.dir, source), data, 0600); err != nil {
+		return errors.Wrapf(err, "unable to write attachment %s", source)
+	}
+	step.Attachments = append(step.Attachments, allureAttachment{Name: name, Source: source, Type: mimeType})
+	return nil
+}
+
+func (w *allureWriter) writeJSON(name string, data interface{}) error {
+	btes, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return errors.Wrapf(err, "unable to marshal %s", name)
+	}
+	filename := filepath.Join(w.dir, name)
+	if err := os.WriteFile(filename, btes, 0600); err != nil {
+		return errors.Wrapf(err, "Error while creating file %s", filename)
+	}
+	return nil
+}
+
+// allureStatus returns the Allure status of a testcase or of a step: failed if an assertion is not verified, broken
+// if there is an error
+func allureStatus(status Status, failures []Failure, skipped []Skipped) (string, *allureStatusDetails) {
+	if len(failures) > 0 {
+		s := "failed"
+		var trace []string
+		for _, f := range failures {
+			if f.Type != FailureTypeAssertion {
+				s = "broken"
+			}
+			trace = append(trace, failureWithDiff(f))
+		}
+		message := failures[0].Message
+		if message == "" {
+			message = failures[0].Value
+		}
+		return s, &allureStatusDetails{Message: message, Trace: strings.Join(trace, "\n")}
+	}
+	if status == StatusSkip {
+		var reasons []string
+		for _, s := range skipped {
+			reasons = append(reasons, s.Value)
+		}
+		if len(reasons) == 0 {
+			return "skipped", nil
+		}
+		return "skipped", &allureStatusDetails{Message: strings.Join(reasons, "\n")}
+	}
+	return "passed", nil
+}
+
+// newUUID returns a random UUID
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:]) // nolint
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
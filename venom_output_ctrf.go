@@ -0,0 +1,174 @@
+package venom
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+type ctrfReport struct {
+	ReportFormat string      `json:"reportFormat"`
+	SpecVersion  string      `json:"specVersion"`
+	Results      ctrfResults `json:"results"`
+}
+
+type ctrfResults struct {
+	Tool        ctrfTool         `json:"tool"`
+	Summary     ctrfSummary      `json:"summary"`
+	Tests       []ctrfTest       `json:"tests"`
+	Environment *ctrfEnvironment `json:"environment,omitempty"`
+}
+
+type ctrfTool struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+type ctrfSummary struct {
+	Tests   int   `json:"tests"`
+	Passed  int   `json:"passed"`
+	Failed  int   `json:"failed"`
+	Pending int   `json:"pending"`
+	Skipped int   `json:"skipped"`
+	Other   int   `json:"other"`
+	Start   int64 `json:"start"`
+	Stop    int64 `json:"stop"`
+}
+
+type ctrfEnvironment struct {
+	Extra map[string]interface{} `json:"extra,omitempty"`
+}
+
+type ctrfTest struct {
+	Name      string                 `json:"name"`
+	Status    string                 `json:"status"`
+	Duration  int64                  `json:"duration"`
+	Start     int64                  `json:"start,omitempty"`
+	Stop      int64                  `json:"stop,omitempty"`
+	Suite     string                 `json:"suite,omitempty"`
+	Message   string                 `json:"message,omitempty"`
+	Trace     string                 `json:"trace,omitempty"`
+	RawStatus string                 `json:"rawStatus,omitempty"`
+	Tags      []string               `json:"tags,omitempty"`
+	FilePath  string                 `json:"filePath,omitempty"`
+	Line      int                    `json:"line,omitempty"`
+	Retries   int                    `json:"retries,omitempty"`
+	Flaky     bool                   `json:"flaky,omitempty"`
+	Stdout    []string               `json:"stdout,omitempty"`
+	Stderr    []string               `json:"stderr,omitempty"`
+	Steps     []ctrfStep             `json:"steps,omitempty"`
+	Extra     map[string]interface{} `json:"extra,omitempty"`
+}
+
+type ctrfStep struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+}
+
+// outputCTRFFormat renders tests results in the Common Test Report Format: https://ctrf.io
+func outputCTRFFormat(tests Tests) ([]byte, error) {
+	report := ctrfReport{
+		ReportFormat: "CTRF",
+		SpecVersion:  "0.0.0",
+		Results: ctrfResults{
+			Tool:  ctrfTool{Name: "venom", Version: Version},
+			Tests: []ctrfTest{},
+			Summary: ctrfSummary{
+				Start: unixMilli(tests.Start),
+				Stop:  unixMilli(tests.End),
+			},
+		},
+	}
+	if tests.Shard != "" {
+		report.Results.Environment = &ctrfEnvironment{Extra: map[string]interface{}{"shard": tests.Shard}}
+	}
+
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			test := ctrfTest{
+				Name:      tc.Name,
+				Status:    ctrfStatus(tc.Status),
+				Duration:  int64(tc.Duration * 1000),
+				Start:     unixMilli(tc.Start),
+				Stop:      unixMilli(tc.End),
+				Suite:     ts.Name,
+				RawStatus: string(tc.Status),
+				Tags:      tc.Tags,
+				FilePath:  ts.Filepath,
+				Line:      tc.Line,
+			}
+			if tc.ID != "" {
+				test.Extra = map[string]interface{}{"id": tc.ID}
+			}
+			if ts.Hostname != "" {
+				if test.Extra == nil {
+					test.Extra = map[string]interface{}{}
+				}
+				test.Extra["hostname"] = ts.Hostname
+			}
+
+			var trace []string
+			for _, res := range tc.TestStepResults {
+				test.Steps = append(test.Steps, ctrfStep{Name: res.Name, Status: ctrfStatus(res.Status)})
+				test.Retries += stepRetries(res)
+				for _, f := range res.Errors {
+					if test.Message == "" {
+						test.Message = f.Message
+						if test.Message == "" {
+							test.Message = f.Value
+						}
+					}
+					trace = append(trace, failureWithDiff(f))
+				}
+				if strings.TrimSpace(res.Systemout) != "" {
+					test.Stdout = append(test.Stdout, res.Systemout)
+				}
+				if strings.TrimSpace(res.Systemerr) != "" {
+					test.Stderr = append(test.Stderr, res.Systemerr)
+				}
+			}
+			test.Trace = strings.Join(trace, "\n")
+			if tc.Status == StatusSkip {
+				var reasons []string
+				for _, s := range tc.Skipped {
+					reasons = append(reasons, s.Value)
+				}
+				test.Message = strings.Join(reasons, "\n")
+			}
+			// a testcase which passed once its steps were retried is flaky
+			test.Flaky = tc.Status == StatusPass && test.Retries > 0
+
+			report.Results.Tests = append(report.Results.Tests, test)
+			report.Results.Summary.Tests++
+			switch test.Status {
+			case "passed":
+				report.Results.Summary.Passed++
+			case "failed":
+				report.Results.Summary.Failed++
+			case "skipped":
+				report.Results.Summary.Skipped++
+			default:
+				report.Results.Summary.Other++
+			}
+		}
+	}
+
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, errors.Wrapf(err, "Error: cannot format output ctrf")
+	}
+	return data, nil
+}
+
+func ctrfStatus(status Status) string {
+	switch status {
+	case StatusPass:
+		return "passed"
+	case StatusFail:
+		return "failed"
+	case StatusSkip:
+		return "skipped"
+	}
+	return "other"
+}
@@ -4,6 +4,8 @@ import (
 	"bytes"
 	_ "embed"
 	"encoding/json"
+	"path/filepath"
+	"sort"
 	"text/template"
 
 	"github.com/pkg/errors"
@@ -12,12 +14,38 @@ import (
 //go:embed venom_output.html
 var templateHTML string
 
+// HTMLHistoryDir is the directory of the JSON reports of the previous runs, in the output directory, shown in the
+// history of the HTML report
+const HTMLHistoryDir = "history"
+
 type TestsHTML struct {
-	Tests     Tests  `json:"tests"`
-	JSONValue string `json:"jsonValue"`
+	Tests       Tests  `json:"tests"`
+	JSONValue   string `json:"jsonValue"`
+	HistoryJSON string `json:"historyJSON"`
+}
+
+// htmlHistory is the pass rate and the duration of the testcases in the previous runs and in the current one
+type htmlHistory struct {
+	Runs      []htmlHistoryResult   `json:"runs"`
+	TestCases []htmlHistoryTestCase `json:"testcases"`
+}
+
+type htmlHistoryTestCase struct {
+	TestSuite string `json:"testsuite"`
+	Name      string `json:"name"`
+	// Results are the results of the testcase in each run, null when it was not run
+	Results []*htmlHistoryResult `json:"results"`
+	Passed  int                  `json:"passed"`
+	Failed  int                  `json:"failed"`
+}
+
+type htmlHistoryResult struct {
+	Start    string  `json:"start"`
+	Status   Status  `json:"status"`
+	Duration float64 `json:"duration"`
 }
 
-func outputHTML(testsResult *Tests) ([]byte, error) {
+func outputHTML(testsResult *Tests, history ...Tests) ([]byte, error) {
 	var buf bytes.Buffer
 
 	testJSON, err := json.MarshalIndent(testsResult, "", " ")
@@ -26,8 +54,16 @@ func outputHTML(testsResult *Tests) ([]byte, error) {
 	}
 
 	testsHTML := TestsHTML{
-		Tests:     *testsResult,
-		JSONValue: string(testJSON),
+		Tests:       *testsResult,
+		JSONValue:   string(testJSON),
+		HistoryJSON: "null",
+	}
+	if len(history) > 0 {
+		historyJSON, err := json.Marshal(newHTMLHistory(append(history, *testsResult)))
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to make json value of the history")
+		}
+		testsHTML.HistoryJSON = string(historyJSON)
 	}
 	tmpl := template.Must(template.New("reportHTML").Parse(templateHTML))
 	if err := tmpl.Execute(&buf, testsHTML); err != nil {
@@ -35,3 +71,59 @@ func outputHTML(testsResult *Tests) ([]byte, error) {
 	}
 	return buf.Bytes(), nil
 }
+
+// newHTMLHistory returns the history of runs, the last one being the current run
+func newHTMLHistory(runs []Tests) htmlHistory {
+	var history htmlHistory
+	index := map[string]int{}
+	for i, run := range runs {
+		history.Runs = append(history.Runs, htmlHistoryResult{Start: run.Start.Format("2006-01-02T15:04:05Z07:00"), Status: run.Status, Duration: run.Duration})
+		for _, ts := range run.TestSuites {
+			for _, tc := range ts.TestCases {
+				key := ts.Filepath + "\x00" + tc.Name
+				j, ok := index[key]
+				if !ok {
+					j = len(history.TestCases)
+					index[key] = j
+					history.TestCases = append(history.TestCases, htmlHistoryTestCase{TestSuite: ts.Name, Name: tc.Name, Results: make([]*htmlHistoryResult, len(runs))})
+				}
+				h := &history.TestCases[j]
+				h.Results[i] = &htmlHistoryResult{Start: tc.Start.Format("2006-01-02T15:04:05Z07:00"), Status: tc.Status, Duration: tc.Duration}
+				switch tc.Status {
+				case StatusPass:
+					h.Passed++
+				case StatusFail:
+					h.Failed++
+				}
+			}
+		}
+	}
+	sort.SliceStable(history.TestCases, func(i, j int) bool {
+		if history.TestCases[i].TestSuite != history.TestCases[j].TestSuite {
+			return history.TestCases[i].TestSuite < history.TestCases[j].TestSuite
+		}
+		return history.TestCases[i].Name < history.TestCases[j].Name
+	})
+	return history
+}
+
+// readHTMLHistory reads the last JSON reports of the history directory, sorted by start. The reports which can't be
+// read are ignored.
+func readHTMLHistory(dir string, max int) ([]Tests, []error) {
+	filenames, _ := filepath.Glob(filepath.Join(dir, "*.json")) // nolint
+	var reports []Tests
+	var errs []error
+	for _, f := range filenames {
+		tests, err := ReadReport(f)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		reports = append(reports, tests)
+	}
+	sort.SliceStable(reports, func(i, j int) bool { return reports[i].Start.Before(reports[j].Start) })
+	if len(reports) > max {
+		reports = reports[len(reports)-max:]
+	}
+	return reports, errs
+}
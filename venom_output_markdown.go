@@ -0,0 +1,155 @@
+package venom
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"sort"
+	"strings"
+)
+
+const (
+	// markdownMaxRows limits the rows of the tables and the details of the markdown report, to keep it small enough
+	// for a CI step summary or a merge request note
+	markdownMaxRows = 50
+	// markdownSlowest is the number of slowest testcases of the markdown report
+	markdownSlowest = 5
+	// markdownMaxValue limits the length of a failure in the table of the failed testcases
+	markdownMaxValue = 200
+)
+
+// outputMarkdownFormat renders a concise summary of tests results in Markdown: the totals, the failed testcases, the
+// slowest testcases and the informations of the steps
+func outputMarkdownFormat(tests Tests) ([]byte, error) {
+	type testCaseRef struct {
+		ts *TestSuite
+		tc *TestCase
+	}
+	var all, failed, withInfo []testCaseRef
+	var passed, skipped int
+	for i := range tests.TestSuites {
+		ts := &tests.TestSuites[i]
+		for j := range ts.TestCases {
+			ref := testCaseRef{ts: ts, tc: &ts.TestCases[j]}
+			all = append(all, ref)
+			switch ref.tc.Status {
+			case StatusPass:
+				passed++
+			case StatusFail:
+				failed = append(failed, ref)
+			case StatusSkip:
+				skipped++
+			}
+			for _, res := range ref.tc.TestStepResults {
+				if len(res.ComputedInfo) > 0 {
+					withInfo = append(withInfo, ref)
+					break
+				}
+			}
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	status := tests.Status
+	if status == "" {
+		status = StatusPass
+		if len(failed) > 0 {
+			status = StatusFail
+		}
+	}
+	icon := "✅"
+	if status == StatusFail {
+		icon = "❌"
+	}
+	fmt.Fprintf(buf, "## %s Venom: %s\n\n", icon, status)
+	if tests.Shard != "" {
+		fmt.Fprintf(buf, "Shard %s\n\n", tests.Shard)
+	}
+	fmt.Fprintf(buf, "| Testsuites | Testcases | Passed | Failed | Skipped | Duration |\n")
+	fmt.Fprintf(buf, "|---:|---:|---:|---:|---:|---:|\n")
+	fmt.Fprintf(buf, "| %d | %d | %d | %d | %d | %.2fs |\n", len(tests.TestSuites), len(all), passed, len(failed), skipped, tests.Duration)
+
+	if len(failed) > 0 {
+		fmt.Fprintf(buf, "\n### Failed testcases\n\n")
+		fmt.Fprintf(buf, "| Testsuite | Testcase | Failure | Location |\n")
+		fmt.Fprintf(buf, "|---|---|---|---|\n")
+		for i, ref := range failed {
+			if i == markdownMaxRows {
+				fmt.Fprintf(buf, "\n… and %d other failed testcase(s)\n", len(failed)-markdownMaxRows)
+				break
+			}
+			var value string
+			line := ref.tc.Line
+			if failure := firstFailure(*ref.tc); failure != nil {
+				value = failure.Value
+				if failure.TestcaseLineNumber > 0 {
+					line = failure.TestcaseLineNumber
+				}
+			}
+			location := ref.ts.Filepath
+			if line > 0 {
+				location = fmt.Sprintf("%s:%d", location, line)
+			}
+			fmt.Fprintf(buf, "| %s | %s | %s | `%s` |\n", markdownCell(ref.ts.Name), markdownCell(ref.tc.Name), markdownCell(value), location)
+		}
+	}
+
+	slowest := make([]testCaseRef, 0, len(all))
+	for _, ref := range all {
+		if ref.tc.Status != StatusSkip {
+			slowest = append(slowest, ref)
+		}
+	}
+	sort.SliceStable(slowest, func(i, j int) bool { return slowest[i].tc.Duration > slowest[j].tc.Duration })
+	if len(slowest) > markdownSlowest {
+		slowest = slowest[:markdownSlowest]
+	}
+	if len(slowest) > 0 {
+		fmt.Fprintf(buf, "\n### Slowest testcases\n\n")
+		fmt.Fprintf(buf, "| Testsuite | Testcase | Duration |\n")
+		fmt.Fprintf(buf, "|---|---|---:|\n")
+		for _, ref := range slowest {
+			fmt.Fprintf(buf, "| %s | %s | %.2fs |\n", markdownCell(ref.ts.Name), markdownCell(ref.tc.Name), ref.tc.Duration)
+		}
+	}
+
+	if len(withInfo) > 0 {
+		fmt.Fprintf(buf, "\n### Details\n")
+		for i, ref := range withInfo {
+			if i == markdownMaxRows {
+				fmt.Fprintf(buf, "\n… and %d other testcase(s)\n", len(withInfo)-markdownMaxRows)
+				break
+			}
+			fmt.Fprintf(buf, "\n<details><summary>%s / %s</summary>\n\n```\n", html.EscapeString(ref.ts.Name), html.EscapeString(ref.tc.Name))
+			for _, res := range ref.tc.TestStepResults {
+				for _, info := range res.ComputedInfo {
+					fmt.Fprintf(buf, "[info] %s\n", strings.ReplaceAll(info, "```", "'''"))
+				}
+			}
+			fmt.Fprintf(buf, "```\n\n</details>\n")
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+// firstFailure returns the first failure of the steps of a testcase, nil if there is none
+func firstFailure(tc TestCase) *Failure {
+	for _, res := range tc.TestStepResults {
+		if len(res.Errors) > 0 {
+			return &res.Errors[0]
+		}
+	}
+	return nil
+}
+
+// markdownEscaper escapes the characters which would be rendered as HTML or break a Markdown table
+var markdownEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;", "|", "\\|")
+
+// markdownCell returns a value on a single line, which can be written in a cell of a Markdown table
+func markdownCell(value string) string {
+	value = strings.Join(strings.Fields(value), " ")
+	if len([]rune(value)) > markdownMaxValue {
+		value = string([]rune(value)[:markdownMaxValue]) + "…"
+	}
+	return markdownEscaper.Replace(value)
+}
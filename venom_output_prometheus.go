@@ -0,0 +1,236 @@
+package venom
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rockbears/yaml"
+)
+
+// prometheusBuckets are the upper bounds, in seconds, of the buckets of the histogram of the durations of the steps
+var prometheusBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// timeoutPrefix starts the failure of a step whose executor timed out
+const timeoutPrefix = "Timeout after "
+
+// outputPrometheusFormat returns the metrics of tests results in the Prometheus text exposition format: the status
+// and the duration of the testcases, labelled with their testsuite, its file, their name and tags, and the durations,
+// the retries and the timeouts of the steps, labelled with their executor. The testsuites are told apart by their file
+// as several testsuites can have the same name.
+func outputPrometheusFormat(tests Tests) []byte {
+	buf := new(bytes.Buffer)
+	success := 0
+	if tests.Status != StatusFail {
+		success = 1
+	}
+	writePrometheusHeader(buf, "venom_run_success", "gauge", "Whether the run passed")
+	fmt.Fprintf(buf, "venom_run_success %d\n", success)
+	writePrometheusHeader(buf, "venom_run_duration_seconds", "gauge", "Duration of the run")
+	fmt.Fprintf(buf, "venom_run_duration_seconds %s\n", prometheusFloat(tests.Duration))
+	if !tests.End.IsZero() {
+		writePrometheusHeader(buf, "venom_run_timestamp_seconds", "gauge", "End of the run, in seconds since the epoch")
+		fmt.Fprintf(buf, "venom_run_timestamp_seconds %d\n", tests.End.Unix())
+	}
+
+	statuses := []Status{StatusPass, StatusFail, StatusSkip}
+	writePrometheusHeader(buf, "venom_testcases_total", "counter", "Testcases run, by testsuite and status")
+	for _, ts := range tests.TestSuites {
+		counts := map[Status]int{}
+		for _, tc := range ts.TestCases {
+			counts[tc.Status]++
+		}
+		for _, status := range statuses {
+			fmt.Fprintf(buf, "venom_testcases_total{%s} %d\n", prometheusLabels("testsuite", ts.Name, "file", ts.Filepath, "status", string(status)), counts[status])
+		}
+	}
+
+	writePrometheusHeader(buf, "venom_testcase_status", "gauge", "Status of the testcases: 1 for the status of the testcase, 0 for the others")
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			for _, status := range statuses {
+				value := 0
+				if tc.Status == status {
+					value = 1
+				}
+				fmt.Fprintf(buf, "venom_testcase_status{%s} %d\n", prometheusTestCaseLabels(ts, tc, "status", string(status)), value)
+			}
+		}
+	}
+	writePrometheusHeader(buf, "venom_testcase_duration_seconds", "gauge", "Duration of the testcases")
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			fmt.Fprintf(buf, "venom_testcase_duration_seconds{%s} %s\n", prometheusTestCaseLabels(ts, tc), prometheusFloat(tc.Duration))
+		}
+	}
+
+	type histogram struct {
+		buckets []int
+		sum     float64
+		count   int
+	}
+	histograms := map[string]*histogram{}
+	var retries, timeouts []string
+	for _, ts := range tests.TestSuites {
+		for _, tc := range ts.TestCases {
+			stepRetriesByExecutor := map[string]int{}
+			stepTimeoutsByExecutor := map[string]int{}
+			var executors []string
+			for _, res := range tc.TestStepResults {
+				if res.Status == StatusSkip || res.Start.IsZero() {
+					continue
+				}
+				executor := stepExecutor(res)
+				if _, ok := stepRetriesByExecutor[executor]; !ok {
+					executors = append(executors, executor)
+				}
+				h, ok := histograms[executor]
+				if !ok {
+					h = &histogram{buckets: make([]int, len(prometheusBuckets))}
+					histograms[executor] = h
+				}
+				for i, bound := range prometheusBuckets {
+					if res.Duration <= bound {
+						h.buckets[i]++
+					}
+				}
+				h.sum += res.Duration
+				h.count++
+				stepRetriesByExecutor[executor] += stepRetries(res)
+				for _, f := range res.Errors {
+					if strings.HasPrefix(f.Message, timeoutPrefix) {
+						stepTimeoutsByExecutor[executor]++
+						break
+					}
+				}
+			}
+			for _, executor := range executors {
+				labels := prometheusTestCaseLabels(ts, tc, "executor", executor)
+				retries = append(retries, fmt.Sprintf("venom_step_retries_total{%s} %d\n", labels, stepRetriesByExecutor[executor]))
+				timeouts = append(timeouts, fmt.Sprintf("venom_step_timeouts_total{%s} %d\n", labels, stepTimeoutsByExecutor[executor]))
+			}
+		}
+	}
+
+	writePrometheusHeader(buf, "venom_step_duration_seconds", "histogram", "Duration of the steps, by executor")
+	executors := make([]string, 0, len(histograms))
+	for executor := range histograms {
+		executors = append(executors, executor)
+	}
+	sort.Strings(executors)
+	for _, executor := range executors {
+		h := histograms[executor]
+		for i, bound := range prometheusBuckets {
+			fmt.Fprintf(buf, "venom_step_duration_seconds_bucket{%s} %d\n", prometheusLabels("executor", executor, "le", prometheusFloat(bound)), h.buckets[i])
+		}
+		fmt.Fprintf(buf, "venom_step_duration_seconds_bucket{%s} %d\n", prometheusLabels("executor", executor, "le", "+Inf"), h.count)
+		fmt.Fprintf(buf, "venom_step_duration_seconds_sum{%s} %s\n", prometheusLabels("executor", executor), prometheusFloat(h.sum))
+		fmt.Fprintf(buf, "venom_step_duration_seconds_count{%s} %d\n", prometheusLabels("executor", executor), h.count)
+	}
+	writePrometheusHeader(buf, "venom_step_retries_total", "counter", "Retries of the steps, by testcase and executor")
+	buf.WriteString(strings.Join(retries, ""))
+	writePrometheusHeader(buf, "venom_step_timeouts_total", "counter", "Steps which timed out, by testcase and executor")
+	buf.WriteString(strings.Join(timeouts, ""))
+	return buf.Bytes()
+}
+
+func writePrometheusHeader(w io.Writer, name, kind, help string) {
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, kind)
+}
+
+// prometheusLabelEscaper escapes the values of the labels, as expected by the text exposition format
+var prometheusLabelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// prometheusLabels formats labels given as name, value pairs
+func prometheusLabels(nameValues ...string) string {
+	labels := make([]string, 0, len(nameValues)/2)
+	for i := 0; i+1 < len(nameValues); i += 2 {
+		labels = append(labels, fmt.Sprintf("%s=\"%s\"", nameValues[i], prometheusLabelEscaper.Replace(nameValues[i+1])))
+	}
+	return strings.Join(labels, ",")
+}
+
+// prometheusTestCaseLabels formats the labels of a testcase, its testsuite, the file of its testsuite, its name and its
+// sorted tags separated by commas, followed by other labels
+func prometheusTestCaseLabels(ts TestSuite, tc TestCase, nameValues ...string) string {
+	tags := append([]string{}, tc.Tags...)
+	sort.Strings(tags)
+	return prometheusLabels(append([]string{"testsuite", ts.Name, "file", ts.Filepath, "testcase", tc.Name, "tags", strings.Join(tags, ",")}, nameValues...)...)
+}
+
+func prometheusFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// stepExecutor returns the type of the executor of a step, read from the interpolated step: exec by default
+func stepExecutor(res TestStepResult) string {
+	var step struct {
+		Type string `json:"type"`
+	}
+	if err := yaml.Unmarshal([]byte(stepContent(res.Interpolated)), &step); err != nil || step.Type == "" {
+		return "exec"
+	}
+	return step.Type
+}
+
+// metricsReporter writes the metrics of the run in a file, for the textfile collector of the node exporter, and
+// pushes them to a Prometheus Pushgateway
+type metricsReporter struct {
+	BaseReporter
+	v *Venom
+}
+
+func (r *metricsReporter) RunEnd(ctx context.Context, tests *Tests) error {
+	v := r.v
+	data := outputPrometheusFormat(*v.withTestSuites(v.cleanedTestSuites()))
+	if v.MetricsFile != "" {
+		// the file is renamed once written, so that the node exporter never reads a partial file
+		tmp := filepath.Join(filepath.Dir(v.MetricsFile), fmt.Sprintf(".%s.%d.tmp", filepath.Base(v.MetricsFile), os.Getpid()))
+		if err := os.WriteFile(tmp, data, 0644); err != nil {
+			return errors.Wrapf(err, "Error while creating file %s", tmp)
+		}
+		if err := os.Rename(tmp, v.MetricsFile); err != nil {
+			os.Remove(tmp) // nolint
+			return errors.Wrapf(err, "Error while writing file %s", v.MetricsFile)
+		}
+		v.PrintFunc("Writing metrics file %s\n", v.MetricsFile)
+	}
+	if v.Pushgateway != "" {
+		if err := pushMetrics(ctx, v.Pushgateway, data); err != nil {
+			return errors.Wrapf(err, "unable to push the metrics to %s", v.Pushgateway)
+		}
+	}
+	return nil
+}
+
+// pushMetrics replaces the metrics of a group of a Pushgateway. The group is the job venom, unless the URL has a
+// /metrics/job/ path.
+func pushMetrics(ctx context.Context, url string, data []byte) error {
+	url = strings.TrimSuffix(url, "/")
+	if !strings.Contains(url, "/metrics/job/") {
+		url += "/metrics/job/venom"
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, url, bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; version=0.0.4")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
@@ -0,0 +1,474 @@
+package venom
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newXMLTestTests() Tests {
+	return Tests{TestSuites: []TestSuite{{
+		Name:     "api",
+		Filename: "api.yml",
+		Filepath: "tests/api.yml",
+		Hostname: "ci-runner",
+		Start:    time.Date(2023, 1, 1, 10, 0, 0, 0, time.UTC),
+		Secrets:  []string{"token"},
+		TestCases: []TestCase{
+			{
+				TestCaseInput: TestCaseInput{Name: "create-user", ID: "API-1", Tags: []string{"smoke"}, Vars: H{
+					"user":           "bob",
+					"auth":           "Bearer s3cr3t",
+					"token":          "s3cr3t",
+					"host":           "localhost",
+					"venom.testcase": "create-user",
+				}},
+				DeclaredVars: H{"user": "bob", "auth": "Bearer s3cr3t"},
+				Line:         3,
+				Status:       StatusFail,
+				TestStepResults: []TestStepResult{
+					{Name: "http", Number: 0, Status: StatusPass, Systemout: "created"},
+					{Name: "http", Number: 1, Status: StatusFail, Errors: []Failure{{
+						Type:               FailureTypeAssertion,
+						Message:            "expected: 200  got: 500",
+						TestcaseLineNumber: 12,
+						Value:              `Assertion "result.statuscode ShouldEqual 200" failed`,
+					}}},
+				},
+			},
+			{
+				TestCaseInput: TestCaseInput{Name: "delete-user"},
+				Line:          15,
+				Status:        StatusFail,
+				TestStepResults: []TestStepResult{
+					{Name: "http", Status: StatusFail, Errors: []Failure{{Type: FailureTypeError, Message: "connection refused", Value: "connection refused"}}},
+				},
+			},
+			{
+				TestCaseInput: TestCaseInput{Name: "list-users"},
+				Status:        StatusSkip,
+				Skipped:       []Skipped{{Value: "skipped"}},
+			},
+		},
+	}}}
+}
+
+func TestOutputXMLFormat(t *testing.T) {
+	data, err := outputXMLFormat(newXMLTestTests(), FormatOptions{})
+	require.NoError(t, err)
+
+	var testsXML TestsXML
+	require.NoError(t, xml.Unmarshal(data, &testsXML))
+	require.Len(t, testsXML.TestSuites, 1)
+	ts := testsXML.TestSuites[0]
+	assert.Equal(t, 3, ts.Total)
+	assert.Equal(t, 1, ts.Failures)
+	assert.Equal(t, 1, ts.Errors)
+	assert.Equal(t, 1, ts.Skipped)
+	assert.Equal(t, "ci-runner", ts.Hostname)
+	assert.Equal(t, "2023-01-01T10:00:00", ts.Timestamp)
+
+	require.Len(t, ts.TestCases, 3)
+	created := ts.TestCases[0]
+	require.Len(t, created.Failures, 1)
+	assert.Empty(t, created.Errors)
+	assert.Equal(t, "assertion", created.Failures[0].Type)
+	assert.Equal(t, "expected: 200  got: 500", created.Failures[0].Message)
+	assert.Equal(t, "tests/api.yml", created.File)
+	assert.Equal(t, 12, created.Line)
+	require.NotNil(t, created.Properties)
+	assert.Equal(t, []PropertyXML{
+		{Name: "id", Value: "API-1"},
+		{Name: "tag", Value: "smoke"},
+		{Name: "var.auth", Value: "Bearer __hidden__"},
+		{Name: "var.user", Value: "bob"},
+	}, created.Properties.Properties)
+	assert.Equal(t, "--- step #0 http\ncreated", created.Systemout.Value)
+
+	deleted := ts.TestCases[1]
+	require.Len(t, deleted.Errors, 1)
+	assert.Empty(t, deleted.Failures)
+	assert.Equal(t, "connection refused", deleted.Errors[0].Message)
+	assert.Equal(t, 15, deleted.Line)
+}
+
+func TestOutputXMLFormatPerStep(t *testing.T) {
+	data, err := outputXMLFormat(newXMLTestTests(), FormatOptions{JUnitPerStep: true})
+	require.NoError(t, err)
+
+	var testsXML TestsXML
+	require.NoError(t, xml.Unmarshal(data, &testsXML))
+	ts := testsXML.TestSuites[0]
+	assert.Equal(t, 4, ts.Total)
+	assert.Equal(t, 1, ts.Failures)
+	assert.Equal(t, 1, ts.Errors)
+	assert.Equal(t, 1, ts.Skipped)
+
+	var names []string
+	for _, tc := range ts.TestCases {
+		names = append(names, tc.Name)
+	}
+	assert.Equal(t, []string{"create-user / #0 http", "create-user / #1 http", "delete-user / #0 http", "list-users"}, names)
+	assert.Empty(t, ts.TestCases[0].Failures)
+	assert.Equal(t, "created", ts.TestCases[0].Systemout.Value)
+	assert.Len(t, ts.TestCases[1].Failures, 1)
+}
+
+func TestCleanUpSecrets(t *testing.T) {
+	v := New()
+	ts := TestSuite{
+		Secrets: []string{"token"},
+		TestCases: []TestCase{{
+			// a skipped testcase has no results
+			TestCaseInput: TestCaseInput{Name: "skipped", Vars: H{"token": "s3cr3t", "auth": "Bearer s3cr3t"}},
+			DeclaredVars:  H{"auth": "Bearer s3cr3t"},
+			Status:        StatusSkip,
+		}},
+	}
+
+	cleaned := v.CleanUpSecrets(ts)
+	assert.Equal(t, "Bearer __hidden__", cleaned.TestCases[0].Vars["auth"])
+	assert.Equal(t, "Bearer __hidden__", cleaned.TestCases[0].DeclaredVars["auth"])
+}
+
+func TestTestCaseLines(t *testing.T) {
+	lines := testCaseLines([]byte(`name: api
+testcases:
+- name: create-user
+  steps:
+  - type: http
+- name: delete-user
+  steps: []
+`))
+	assert.Equal(t, []int{3, 6}, lines)
+	assert.Nil(t, testCaseLines([]byte(`name: [`)))
+}
+
+func TestOutputCTRFFormat(t *testing.T) {
+	tests := newXMLTestTests()
+	tests.TestSuites[0].TestCases = append(tests.TestSuites[0].TestCases, TestCase{
+		TestCaseInput: TestCaseInput{Name: "update-user"},
+		Status:        StatusPass,
+		TestStepResults: []TestStepResult{
+			{Name: "http", Status: StatusPass, Retries: 2},
+		},
+	})
+	data, err := outputCTRFFormat(tests)
+	require.NoError(t, err)
+
+	var report ctrfReport
+	require.NoError(t, json.Unmarshal(data, &report))
+	assert.Equal(t, "CTRF", report.ReportFormat)
+	assert.Equal(t, ctrfSummary{Tests: 4, Passed: 1, Failed: 2, Skipped: 1}, report.Results.Summary)
+
+	require.Len(t, report.Results.Tests, 4)
+	created := report.Results.Tests[0]
+	assert.Equal(t, "failed", created.Status)
+	assert.Equal(t, "expected: 200  got: 500", created.Message)
+	assert.Equal(t, []string{"smoke"}, created.Tags)
+	assert.Equal(t, "tests/api.yml", created.FilePath)
+	assert.Equal(t, 3, created.Line)
+	assert.Equal(t, "API-1", created.Extra["id"])
+	assert.Equal(t, []string{"created"}, created.Stdout)
+	assert.False(t, created.Flaky)
+
+	assert.Equal(t, "skipped", report.Results.Tests[2].Status)
+	assert.Equal(t, "skipped", report.Results.Tests[2].Message)
+
+	updated := report.Results.Tests[3]
+	assert.Equal(t, 2, updated.Retries)
+	assert.True(t, updated.Flaky)
+}
+
+func TestWriteAllureResults(t *testing.T) {
+	tests := newXMLTestTests()
+	tests.TestSuites[0].TestCases = append(tests.TestSuites[0].TestCases, TestCase{
+		TestCaseInput: TestCaseInput{Name: "ranged"},
+		Status:        StatusPass,
+		TestStepResults: []TestStepResult{
+			{Name: "exec (range=0)", RangedEnable: true, RangedIndex: 0, Status: StatusPass, Interpolated: "type: exec"},
+			{Name: "exec (range=1)", RangedEnable: true, RangedIndex: 1, Status: StatusPass, DumpFile: "removed.dump.json"},
+			{Name: "exec (aggregate)", RangedEnable: true, Status: StatusPass},
+		},
+	})
+	dump := filepath.Join(t.TempDir(), "api.create-user.step.1.0.dump.json")
+	require.NoError(t, os.WriteFile(dump, []byte(`{"result": {"statuscode": 500}}`), 0644))
+	failed := &tests.TestSuites[0].TestCases[0].TestStepResults[1]
+	failed.ComputedVars = H{"result.statuscode": 500, "venom.testcase": "create-user"}
+	failed.DumpFile = dump
+	dir := t.TempDir()
+	require.NoError(t, WriteAllureResults(dir, &tests))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*-result.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 4)
+
+	results := map[string]allureResult{}
+	for _, f := range files {
+		btes, err := os.ReadFile(f)
+		require.NoError(t, err)
+		var result allureResult
+		require.NoError(t, json.Unmarshal(btes, &result))
+		results[result.Name] = result
+	}
+
+	created := results["create-user"]
+	assert.Equal(t, "failed", created.Status)
+	assert.Equal(t, "API-1", created.TestCaseID)
+	assert.Contains(t, created.Labels, allureLabel{Name: "tag", Value: "smoke"})
+	assert.Contains(t, created.Labels, allureLabel{Name: "host", Value: "ci-runner"})
+	require.Len(t, created.Steps, 2)
+	require.Len(t, created.Steps[0].Attachments, 1)
+	assert.Equal(t, "systemout", created.Steps[0].Attachments[0].Name)
+	_, err = os.Stat(filepath.Join(dir, created.Steps[0].Attachments[0].Source))
+	assert.NoError(t, err)
+	require.Len(t, created.Steps[1].Attachments, 2)
+	assert.Equal(t, "variables", created.Steps[1].Attachments[0].Name)
+	variables, err := os.ReadFile(filepath.Join(dir, created.Steps[1].Attachments[0].Source))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"result.statuscode": 500}`, string(variables))
+	assert.Equal(t, "dump", created.Steps[1].Attachments[1].Name)
+	data, err := os.ReadFile(filepath.Join(dir, created.Steps[1].Attachments[1].Source))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"result": {"statuscode": 500}}`, string(data))
+
+	assert.Equal(t, "broken", results["delete-user"].Status)
+	assert.Equal(t, "skipped", results["list-users"].Status)
+
+	ranged := results["ranged"]
+	require.Len(t, ranged.Steps, 1)
+	assert.Equal(t, "exec", ranged.Steps[0].Name)
+	assert.Equal(t, "passed", ranged.Steps[0].Status)
+	require.Len(t, ranged.Steps[0].Steps, 3)
+	require.Len(t, ranged.Steps[0].Steps[0].Attachments, 1)
+	assert.Equal(t, "step", ranged.Steps[0].Steps[0].Attachments[0].Name)
+	// the dump files which no longer exist are not attached
+	assert.Empty(t, ranged.Steps[0].Steps[1].Attachments)
+}
+
+func TestOutputMarkdownFormat(t *testing.T) {
+	tests := newXMLTestTests()
+	tests.Status = StatusFail
+	tests.Duration = 1.5
+	tests.TestSuites[0].TestCases[0].Duration = 1.2
+	tests.TestSuites[0].TestCases[0].TestStepResults[0].ComputedInfo = []string{"user id is 42"}
+	tests.TestSuites[0].TestCases[1].TestStepResults[0].Errors[0].Value = "connection | refused\non port 80"
+
+	data, err := outputMarkdownFormat(tests)
+	require.NoError(t, err)
+	md := string(data)
+	assert.Contains(t, md, "## ❌ Venom: FAIL")
+	assert.Contains(t, md, "| 1 | 3 | 0 | 2 | 1 | 1.50s |")
+	assert.Contains(t, md, "| api | create-user | Assertion \"result.statuscode ShouldEqual 200\" failed | `tests/api.yml:12` |")
+	assert.Contains(t, md, "| api | delete-user | connection \\| refused on port 80 | `tests/api.yml:15` |")
+	assert.Contains(t, md, "### Slowest testcases\n\n| Testsuite | Testcase | Duration |\n|---|---|---:|\n| api | create-user | 1.20s |")
+	assert.NotContains(t, md, "| list-users |")
+	assert.Contains(t, md, "<details><summary>api / create-user</summary>\n\n```\n[info] user id is 42\n```")
+}
+
+func TestParseFormats(t *testing.T) {
+	formats, err := ParseFormats(" xml, json,,xml,tap ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"xml", "json", "tap"}, formats)
+
+	_, err = ParseFormats("xml,pdf")
+	assert.EqualError(t, err, `invalid format "pdf": must be json, tap, xml, yml, yaml, html, ctrf, allure or markdown`)
+}
+
+func TestOutputFormats(t *testing.T) {
+	for _, tt := range []struct {
+		mode  string
+		files []string
+	}{
+		{mode: "", files: []string{"test_results_greet.json", "test_results_greet.tap", "test_results_greet.xml", "test_results_hello.json", "test_results_hello.tap", "test_results_hello.xml"}},
+		{mode: OutputModeAggregate, files: []string{"test_results.html", "test_results.json", "test_results.tap", "test_results.xml"}},
+	} {
+		t.Run(tt.mode, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "greet.yml"), []byte(reporterTestSuite), 0644))
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "hello.yml"), []byte("name: hello\ntestcases:\n- name: hello\n  steps:\n  - type: echo\n    value: hello\n"), 0644))
+			outputDir := filepath.Join(dir, "results")
+			_, err := Run(context.Background(), Options{
+				Paths:        []string{filepath.Join(dir, "greet.yml"), filepath.Join(dir, "hello.yml")},
+				Variables:    map[string]interface{}{"who": "world"},
+				Executors:    map[string]Executor{"echo": &debugTestExecutor{}},
+				OutputDir:    outputDir,
+				OutputFormat: "xml,json,tap",
+				OutputMode:   tt.mode,
+				HtmlReport:   tt.mode == OutputModeAggregate,
+				LogOutput:    io.Discard,
+			})
+			require.NoError(t, err)
+
+			entries, err := os.ReadDir(outputDir)
+			require.NoError(t, err)
+			var files []string
+			for _, e := range entries {
+				// the files per testsuite are named after the path of the testsuites
+				files = append(files, strings.ReplaceAll(e.Name(), strings.ReplaceAll(dir, "/", "_")+"_", ""))
+			}
+			assert.Equal(t, tt.files, files)
+		})
+	}
+
+	_, err := NewWithOptions(Options{OutputMode: "single", LogOutput: io.Discard})
+	assert.EqualError(t, err, `invalid output mode "single": must be per-suite or aggregate`)
+}
+
+func TestHTMLHistory(t *testing.T) {
+	dir := t.TempDir()
+	for i, status := range []Status{StatusPass, StatusFail, StatusPass} {
+		tests := newXMLTestTests()
+		tests.Start = time.Date(2023, 1, i+1, 10, 0, 0, 0, time.UTC)
+		tests.Status = status
+		tests.TestSuites[0].TestCases[1].Status = status
+		if i == 0 {
+			// the testcase was added by the last runs
+			tests.TestSuites[0].TestCases = tests.TestSuites[0].TestCases[1:]
+		}
+		data, err := json.Marshal(tests)
+		require.NoError(t, err)
+		// the names of the files aren't sorted as the runs
+		require.NoError(t, os.WriteFile(filepath.Join(dir, string(rune('c'-i))+".json"), data, 0644))
+	}
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "invalid.json"), []byte("{"), 0644))
+
+	runs, errs := readHTMLHistory(dir, 2)
+	assert.Len(t, errs, 1)
+	require.Len(t, runs, 2)
+	assert.Equal(t, 2, runs[0].Start.Day())
+	assert.Equal(t, 3, runs[1].Start.Day())
+
+	runs, _ = readHTMLHistory(dir, 10)
+	require.Len(t, runs, 3)
+	history := newHTMLHistory(runs)
+	require.Len(t, history.Runs, 3)
+	assert.Equal(t, "2023-01-01T10:00:00Z", history.Runs[0].Start)
+	assert.Equal(t, StatusFail, history.Runs[1].Status)
+
+	var names []string
+	for _, tc := range history.TestCases {
+		names = append(names, tc.Name)
+	}
+	assert.Equal(t, []string{"create-user", "delete-user", "list-users"}, names)
+	assert.Nil(t, history.TestCases[0].Results[0])
+	assert.Equal(t, StatusFail, history.TestCases[0].Results[1].Status)
+	assert.Equal(t, 0, history.TestCases[0].Passed)
+	assert.Equal(t, 2, history.TestCases[0].Failed)
+	assert.Equal(t, 2, history.TestCases[1].Passed)
+	assert.Equal(t, 1, history.TestCases[1].Failed)
+
+	current := newXMLTestTests()
+	data, err := outputHTML(&current, runs...)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"testcases":[{"testsuite":"api","name":"create-user"`)
+
+	data, err = outputHTML(&current)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "runsHistory = null")
+}
+
+func TestOutputPrometheusFormat(t *testing.T) {
+	tests := newXMLTestTests()
+	tests.Status = StatusFail
+	tests.Duration = 1.5
+	tc := &tests.TestSuites[0].TestCases[0]
+	tc.Duration = 1.2
+	for i := range tc.TestStepResults {
+		tc.TestStepResults[i].Start = tests.TestSuites[0].Start
+		tc.TestStepResults[i].Interpolated = []byte("type: http\nmethod: GET\n")
+	}
+	tc.TestStepResults[0].Duration = 0.2
+	tc.TestStepResults[1].Duration = 3
+	tc.TestStepResults[1].Retries = 3
+	tc = &tests.TestSuites[0].TestCases[1]
+	tc.TestStepResults[0].Start = tests.TestSuites[0].Start
+	tc.TestStepResults[0].Duration = 5
+	tc.TestStepResults[0].Errors[0].Message = "Timeout after 5 second(s)"
+
+	metrics := string(outputPrometheusFormat(tests))
+	assert.Contains(t, metrics, "# TYPE venom_run_success gauge\nvenom_run_success 0\n")
+	assert.Contains(t, metrics, `venom_testcases_total{testsuite="api",file="tests/api.yml",status="FAIL"} 2`)
+	assert.Contains(t, metrics, `venom_testcases_total{testsuite="api",file="tests/api.yml",status="SKIP"} 1`)
+	assert.Contains(t, metrics, `venom_testcase_status{testsuite="api",file="tests/api.yml",testcase="create-user",tags="smoke",status="FAIL"} 1`)
+	assert.Contains(t, metrics, `venom_testcase_status{testsuite="api",file="tests/api.yml",testcase="create-user",tags="smoke",status="PASS"} 0`)
+	assert.Contains(t, metrics, `venom_testcase_duration_seconds{testsuite="api",file="tests/api.yml",testcase="create-user",tags="smoke"} 1.2`)
+	assert.Contains(t, metrics, `venom_step_duration_seconds_bucket{executor="http",le="0.25"} 1`+"\n")
+	assert.Contains(t, metrics, `venom_step_duration_seconds_bucket{executor="http",le="+Inf"} 2`+"\n")
+	assert.Contains(t, metrics, `venom_step_duration_seconds_sum{executor="http"} 3.2`+"\n")
+	assert.Contains(t, metrics, `venom_step_duration_seconds_count{executor="exec"} 1`+"\n")
+	assert.Contains(t, metrics, `venom_step_retries_total{testsuite="api",file="tests/api.yml",testcase="create-user",tags="smoke",executor="http"} 2`)
+	assert.Contains(t, metrics, `venom_step_timeouts_total{testsuite="api",file="tests/api.yml",testcase="create-user",tags="smoke",executor="http"} 0`)
+	assert.Contains(t, metrics, `venom_step_timeouts_total{testsuite="api",file="tests/api.yml",testcase="delete-user",tags="",executor="exec"} 1`)
+	assert.NotContains(t, metrics, `testcase="list-users",tags="",executor=`)
+
+	assert.Equal(t, `testsuite="a\"b\\c\nd",file="",testcase="",tags="",status="PASS"`, prometheusTestCaseLabels(TestSuite{Name: "a\"b\\c\nd"}, TestCase{}, "status", "PASS"))
+}
+
+func TestOutputPrometheusFormatSameTestSuiteNames(t *testing.T) {
+	tests := Tests{TestSuites: []TestSuite{
+		{Name: "api", Filepath: "tests/v1/api.yml", TestCases: []TestCase{{TestCaseInput: TestCaseInput{Name: "get"}, Status: StatusPass}}},
+		{Name: "api", Filepath: "tests/v2/api.yml", TestCases: []TestCase{{TestCaseInput: TestCaseInput{Name: "get"}, Status: StatusFail}}},
+	}}
+
+	metrics := string(outputPrometheusFormat(tests))
+	assert.Contains(t, metrics, `venom_testcases_total{testsuite="api",file="tests/v1/api.yml",status="PASS"} 1`)
+	assert.Contains(t, metrics, `venom_testcases_total{testsuite="api",file="tests/v2/api.yml",status="FAIL"} 1`)
+	assert.Contains(t, metrics, `venom_testcase_status{testsuite="api",file="tests/v2/api.yml",testcase="get",tags="",status="FAIL"} 1`)
+
+	// each series has a single sample
+	series := map[string]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(metrics), "\n") {
+		if strings.HasPrefix(line, "#") {
+			continue
+		}
+		name := line[:strings.LastIndex(line, " ")]
+		assert.False(t, series[name], "duplicate sample of %s", name)
+		series[name] = true
+	}
+}
+
+func TestMetricsReporter(t *testing.T) {
+	var pushed, path string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		path = r.URL.Path
+		data, _ := io.ReadAll(r.Body)
+		pushed = string(data)
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "greet.yml"), []byte(reporterTestSuite), 0644))
+	_, err := Run(context.Background(), Options{
+		Paths:       []string{filepath.Join(dir, "greet.yml")},
+		Variables:   map[string]interface{}{"who": "world"},
+		Executors:   map[string]Executor{"echo": &debugTestExecutor{}},
+		MetricsFile: filepath.Join(dir, "venom.prom"),
+		Pushgateway: server.URL,
+		LogOutput:   io.Discard,
+	})
+	require.NoError(t, err)
+
+	data, err := os.ReadFile(filepath.Join(dir, "venom.prom"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `venom_testcases_total{testsuite="greet",file="`+filepath.Join(dir, "greet.yml")+`",status="PASS"} 1`)
+	assert.Contains(t, string(data), `venom_step_duration_seconds_count{executor="echo"} 2`)
+	assert.Equal(t, string(data), pushed)
+	assert.Equal(t, "/metrics/job/venom", path)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2, "the temporary file is renamed")
+}
@@ -0,0 +1,125 @@
+// Package venomtest runs venom testsuites as Go subtests, to keep venom integration tests inside go test.
+package venomtest
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ovh/venom"
+	"github.com/ovh/venom/executors"
+)
+
+// Options are the options of the testsuites run by Run
+type Options struct {
+	venom.Options
+	// Parallel runs the testsuites in parallel. The testcases of a testsuite are always run sequentially.
+	Parallel bool
+}
+
+// Run runs the testsuites selected by pattern as subtests of t: one subtest per testsuite, with one subtest per
+// testcase. The failures of the steps are reported with t.Errorf, and the testcases filtered out with -run are
+// skipped. Without executors in opts, the builtin executors of venom are used. Without LogOutput in opts, the logs
+// are written with t.Log.
+func Run(t *testing.T, pattern string, opts Options) {
+	t.Helper()
+
+	files, err := venom.TestSuiteFiles([]string{pattern})
+	if err != nil {
+		t.Fatalf("unable to find testsuites %q: %v", pattern, err)
+	}
+
+	for _, file := range files {
+		file := file
+		t.Run(testSuiteName(file), func(t *testing.T) {
+			if opts.Parallel {
+				t.Parallel()
+			}
+			runTestSuite(t, file, opts.Options)
+		})
+	}
+}
+
+func runTestSuite(t *testing.T, file string, opts venom.Options) {
+	opts.Paths = []string{file}
+	if opts.Executors == nil {
+		// executors are instantiated for each testsuite, as testsuites can run in parallel
+		opts.Executors = map[string]venom.Executor{}
+		for name, executorFunc := range executors.Registry {
+			opts.Executors[name] = executorFunc()
+		}
+	}
+	if opts.LogOutput == nil {
+		opts.LogOutput = testLogWriter{t}
+	}
+	opts.TestCaseWrapper = func(ctx context.Context, ts *venom.TestSuite, tc *venom.TestCase, run func(ctx context.Context)) {
+		t.Run(tc.Name, func(t *testing.T) {
+			run(ctx)
+			reportTestCase(t, tc)
+		})
+	}
+
+	v, err := venom.NewWithOptions(opts)
+	if err != nil {
+		t.Fatalf("unable to instantiate venom: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := v.Close(); err != nil {
+			t.Errorf("unable to close venom: %v", err)
+		}
+	})
+
+	if _, err := v.Run(context.Background(), opts.Paths); err != nil {
+		t.Fatalf("unable to run testsuite %s: %v", file, err)
+	}
+}
+
+// reportTestCase reports the failures and the warnings of the steps of a testcase
+func reportTestCase(t testing.TB, tc *venom.TestCase) {
+	skipped := 0
+	for _, r := range tc.TestStepResults {
+		for _, w := range r.Warnings {
+			t.Logf("[warn] %s", w.Value)
+		}
+		for _, f := range r.Errors {
+			if f.Diff != nil {
+				t.Errorf("%s\n%s", f.Value, venom.FormatDiff(f.Diff, "  ", false))
+				continue
+			}
+			t.Errorf("%s", f.Value)
+		}
+		if r.Status == venom.StatusSkip {
+			skipped++
+		}
+	}
+	if t.Failed() {
+		return
+	}
+	if len(tc.Skipped) > 0 {
+		reasons := make([]string, 0, len(tc.Skipped))
+		for _, s := range tc.Skipped {
+			reasons = append(reasons, s.Value)
+		}
+		t.Skip(strings.Join(reasons, ", "))
+	}
+	if skipped == len(tc.TestStepResults) {
+		t.Skip("all steps were skipped")
+	}
+}
+
+// testSuiteName is the name of the subtest of a testsuite: its filename without extension
+func testSuiteName(file string) string {
+	name := filepath.Base(file)
+	return strings.TrimSuffix(name, filepath.Ext(name))
+}
+
+// testLogWriter writes the logs of venom with t.Log
+type testLogWriter struct {
+	t *testing.T
+}
+
+func (w testLogWriter) Write(p []byte) (int, error) {
+	w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	return len(p), nil
+}
@@ -0,0 +1,129 @@
+package venomtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ovh/venom"
+)
+
+type echoExecutor struct {
+	runs *int32
+}
+
+func (e echoExecutor) Run(ctx context.Context, step venom.TestStep) (interface{}, error) {
+	atomic.AddInt32(e.runs, 1)
+	out, _ := step.StringValue("value")
+	return map[string]interface{}{"out": out}, nil
+}
+
+func TestRun(t *testing.T) {
+	var runs int32
+	t.Run("suites", func(t *testing.T) {
+		Run(t, "testdata/*.yml", Options{
+			Options: venom.Options{
+				Executors: map[string]venom.Executor{"echo": echoExecutor{runs: &runs}},
+				LogOutput: io.Discard,
+			},
+			Parallel: true,
+		})
+	})
+	assert.Equal(t, int32(2), atomic.LoadInt32(&runs))
+}
+
+// recordTB records the failures and the skips of a testcase
+type recordTB struct {
+	testing.TB
+	errors []string
+	skips  []string
+}
+
+func (r *recordTB) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func (r *recordTB) Logf(format string, args ...interface{}) {}
+
+func (r *recordTB) Failed() bool {
+	return len(r.errors) > 0
+}
+
+func (r *recordTB) Skip(args ...interface{}) {
+	r.skips = append(r.skips, fmt.Sprint(args...))
+}
+
+func TestReportTestCase(t *testing.T) {
+	tests, err := venom.Run(context.Background(), venom.Options{
+		Paths:     []string{"testdata/failing/failing.yml"},
+		Executors: map[string]venom.Executor{"echo": echoExecutor{runs: new(int32)}},
+		LogOutput: io.Discard,
+	})
+	require.NoError(t, err)
+	testcases := tests.TestSuites[0].TestCases
+	require.Len(t, testcases, 3)
+
+	hello := &recordTB{}
+	reportTestCase(hello, &testcases[0])
+	assert.Empty(t, hello.errors)
+	assert.Empty(t, hello.skips)
+
+	goodbye := &recordTB{}
+	reportTestCase(goodbye, &testcases[1])
+	require.Len(t, goodbye.errors, 1)
+	assert.Contains(t, goodbye.errors[0], `Assertion "out ShouldEqual \"goodbye nobody\"" failed`)
+	assert.Contains(t, goodbye.errors[0], "(failing.yml:16)")
+	assert.Empty(t, goodbye.skips)
+
+	skipped := &recordTB{}
+	reportTestCase(skipped, &testcases[2])
+	assert.Empty(t, skipped.errors)
+	// t.Skip stops a real testcase, only the first skip matters
+	require.NotEmpty(t, skipped.skips)
+	assert.Contains(t, skipped.skips[0], `skipping testcase "skipped"`)
+}
+
+// TestRunFailing runs the failing testsuite in a go test subprocess, as its failures fail the test
+func TestRunFailing(t *testing.T) {
+	if os.Getenv("VENOMTEST_FAILING") != "" {
+		Run(t, "testdata/failing/*.yml", Options{
+			Options: venom.Options{
+				Executors: map[string]venom.Executor{"echo": echoExecutor{runs: new(int32)}},
+				LogOutput: io.Discard,
+			},
+		})
+		return
+	}
+
+	run := func(pattern string) string {
+		cmd := exec.Command(os.Args[0], "-test.v", "-test.run", pattern)
+		cmd.Env = append(os.Environ(), "VENOMTEST_FAILING=1")
+		out, err := cmd.CombinedOutput()
+		if pattern == "^TestRunFailing$/failing/^hello$" {
+			require.NoError(t, err, string(out))
+		} else {
+			require.Error(t, err, string(out))
+		}
+		return string(out)
+	}
+
+	out := run("^TestRunFailing$")
+	assert.Contains(t, out, "--- PASS: TestRunFailing/failing/hello")
+	assert.Contains(t, out, "--- FAIL: TestRunFailing/failing/goodbye")
+	assert.Contains(t, out, "(failing.yml:16)")
+	assert.Contains(t, out, "--- SKIP: TestRunFailing/failing/skipped")
+
+	// the testcases filtered out with -run are not run
+	out = run("^TestRunFailing$/failing/^hello$")
+	assert.Contains(t, out, "--- PASS: TestRunFailing/failing/hello")
+	assert.False(t, strings.Contains(out, "goodbye"), out)
+	assert.False(t, strings.Contains(out, "TestRunFailing/failing/skipped"), out)
+}
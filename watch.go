@@ -0,0 +1,228 @@
+package venom
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// DefaultWatchInterval is the interval between two checks of the watched files
+const DefaultWatchInterval = time.Second
+
+// watchedFiles is a snapshot of the files watched by Watch
+type watchedFiles struct {
+	testsuites []string
+	// userExecutors associates each user executor file with the executors it defines and the executors it uses
+	userExecutors map[string]watchedUserExecutor
+	modTimes      map[string]time.Time
+}
+
+type watchedUserExecutor struct {
+	name string
+	uses []string
+}
+
+// Reset clears the results and the variables of a previous run, so that tests can be run again with the same instance
+func (v *Venom) Reset() {
+	v.Tests = Tests{TestSuites: []TestSuite{}}
+	v.variables = H{}
+}
+
+// InvalidateExecutorFiles removes user executor files from the cache, they will be read again on their next use
+func (v *Venom) InvalidateExecutorFiles(files ...string) {
+	for _, f := range files {
+		delete(v.executorFileCache, f)
+	}
+	// user executors are registered again from the files on each use
+	v.executorsUser = map[string]Executor{}
+}
+
+// Watch calls run with all the testsuites selected by path, then polls the testsuites, the user executors
+// and varFiles until ctx is done. When files change, run is called again with the affected testsuites only.
+func (v *Venom) Watch(ctx context.Context, path []string, varFiles []string, interval time.Duration, run func(ctx context.Context, testsuites []string)) error {
+	previous, err := v.snapshotWatchedFiles(path, varFiles)
+	if err != nil {
+		return err
+	}
+	run(ctx, previous.testsuites)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		current, err := v.snapshotWatchedFiles(path, varFiles)
+		if err != nil {
+			// the selection may be temporarily empty while files are moved
+			continue
+		}
+		changed := changedFiles(previous.modTimes, current.modTimes)
+		if len(changed) == 0 {
+			continue
+		}
+		testsuites := v.affectedTestSuites(changed, previous, current, varFiles)
+		previous = current
+		if len(testsuites) > 0 {
+			run(ctx, testsuites)
+		}
+	}
+}
+
+func (v *Venom) snapshotWatchedFiles(path []string, varFiles []string) (*watchedFiles, error) {
+	testsuites, err := getFilesPath(path)
+	if err != nil {
+		return nil, err
+	}
+	w := &watchedFiles{
+		testsuites:    testsuites,
+		userExecutors: map[string]watchedUserExecutor{},
+		modTimes:      map[string]time.Time{},
+	}
+
+	files := append([]string{}, testsuites...)
+	workdirs := map[string]bool{}
+	for _, f := range testsuites {
+		workdir, err := filepath.Abs(filepath.Dir(f))
+		if err != nil || workdirs[workdir] {
+			continue
+		}
+		workdirs[workdir] = true
+		executorsPath, _ := v.getUserExecutorFilesPath(map[string]string{"venom.testsuite.workdir": workdir})
+		for _, ef := range executorsPath {
+			if _, ok := w.userExecutors[ef]; ok {
+				continue
+			}
+			w.userExecutors[ef] = v.readWatchedUserExecutor(ef)
+			files = append(files, ef)
+		}
+	}
+	for _, f := range varFiles {
+		if f != "" {
+			files = append(files, f)
+		}
+	}
+
+	for _, f := range files {
+		fi, err := os.Stat(f)
+		if err != nil {
+			continue
+		}
+		w.modTimes[f] = fi.ModTime()
+	}
+	return w, nil
+}
+
+func (v *Venom) readWatchedUserExecutor(filename string) watchedUserExecutor {
+	var ux watchedUserExecutor
+	root, err := v.parseYAMLNode(filename)
+	if err != nil || root == nil {
+		return ux
+	}
+	ux.name = scalarValue(mappingValue(root, "executor"))
+	if steps := mappingValue(root, "steps"); steps != nil {
+		for _, step := range steps.Content {
+			if name := scalarValue(mappingValue(step, "type")); name != "" {
+				ux.uses = append(ux.uses, name)
+			}
+		}
+	}
+	return ux
+}
+
+// changedFiles returns the files added, modified or removed between two snapshots
+func changedFiles(previous, current map[string]time.Time) []string {
+	var changed []string
+	for f, t := range current {
+		if pt, ok := previous[f]; !ok || !pt.Equal(t) {
+			changed = append(changed, f)
+		}
+	}
+	for f := range previous {
+		if _, ok := current[f]; !ok {
+			changed = append(changed, f)
+		}
+	}
+	sort.Strings(changed)
+	return changed
+}
+
+// affectedTestSuites returns the testsuites to run again after some files changed:
+//   - all the testsuites when a var file changed
+//   - the testsuites using a user executor whose file changed, even through another user executor
+//   - the testsuites which changed
+func (v *Venom) affectedTestSuites(changed []string, previous, current *watchedFiles, varFiles []string) []string {
+	all := false
+	changedTestSuites := map[string]bool{}
+	changedExecutors := map[string]bool{}
+	for _, f := range changed {
+		if isInList(f, varFiles) {
+			all = true
+			continue
+		}
+		ux, isExecutor := current.userExecutors[f]
+		if !isExecutor {
+			ux, isExecutor = previous.userExecutors[f]
+		}
+		if isExecutor {
+			v.InvalidateExecutorFiles(f)
+			if ux.name == "" {
+				all = true
+			}
+			changedExecutors[ux.name] = true
+			continue
+		}
+		changedTestSuites[f] = true
+	}
+	if all {
+		return current.testsuites
+	}
+
+	// executors using a changed executor are changed too
+	for found := true; found; {
+		found = false
+		for _, ux := range current.userExecutors {
+			if changedExecutors[ux.name] {
+				continue
+			}
+			for _, u := range ux.uses {
+				if changedExecutors[u] {
+					changedExecutors[ux.name] = true
+					found = true
+					break
+				}
+			}
+		}
+	}
+
+	var testsuites []string
+	for _, f := range current.testsuites {
+		if changedTestSuites[f] {
+			testsuites = append(testsuites, f)
+			continue
+		}
+		if len(changedExecutors) == 0 {
+			continue
+		}
+		workdir, _ := filepath.Abs(filepath.Dir(f))
+		for _, tc := range v.inventoryTestSuite(f, workdir, nil).TestCases {
+			var uses bool
+			for _, e := range tc.Executors {
+				if changedExecutors[e.Name] {
+					uses = true
+					break
+				}
+			}
+			if uses {
+				testsuites = append(testsuites, f)
+				break
+			}
+		}
+	}
+	return testsuites
+}
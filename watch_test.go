@@ -0,0 +1,48 @@
+package venom
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAffectedTestSuites(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "lib"), 0755))
+	files := map[string]string{
+		"lib/base.yml": "executor: base\nsteps:\n- script: echo base\n",
+		"lib/top.yml":  "executor: top\nsteps:\n- type: base\n",
+		"a.yml":        "name: a\ntestcases:\n- name: a\n  steps:\n  - type: top\n",
+		"b.yml":        "name: b\ntestcases:\n- name: b\n  steps:\n  - script: echo b\n",
+		"vars.yml":     "foo: bar\n",
+	}
+	for f, content := range files {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, f), []byte(content), 0644))
+	}
+	a, b := filepath.Join(dir, "a.yml"), filepath.Join(dir, "b.yml")
+	varFiles := []string{filepath.Join(dir, "vars.yml")}
+
+	v := New()
+	v.executorFileCache[filepath.Join(dir, "lib", "base.yml")] = []byte("cached")
+	w, err := v.snapshotWatchedFiles([]string{a, b}, varFiles)
+	require.NoError(t, err)
+	assert.Len(t, w.modTimes, 5)
+
+	assert.Equal(t, []string{b}, v.affectedTestSuites([]string{b}, w, w, varFiles))
+	assert.Equal(t, []string{a, b}, v.affectedTestSuites(varFiles, w, w, varFiles))
+
+	// base is used by top, which is used by a
+	assert.Equal(t, []string{a}, v.affectedTestSuites([]string{filepath.Join(dir, "lib", "base.yml")}, w, w, varFiles))
+	assert.NotContains(t, v.executorFileCache, filepath.Join(dir, "lib", "base.yml"))
+}
+
+func TestChangedFiles(t *testing.T) {
+	now := time.Now()
+	previous := map[string]time.Time{"a": now, "b": now, "c": now}
+	current := map[string]time.Time{"a": now, "b": now.Add(time.Second), "d": now}
+	assert.Equal(t, []string{"b", "c", "d"}, changedFiles(previous, current))
+}